@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/Uri2001/shh/pkg/shh"
+)
+
+var ansiResetAndShowCursor = "\x1b[0m\x1b[?25h"
+
+// execInUserShellLogin connects to h. ssh_config aliases are handed to ssh
+// as-is so ssh itself resolves HostName/User/Port/ProxyJump; everything else
+// is composed into explicit flags from whatever metadata we have on file.
+func execInUserShellLogin(h shh.Host) {
+	args, err := sshArgsFor(h)
+	if err != nil {
+		log.Fatalf("invalid host: %v", err)
+	}
+	cleanupTerminal()
+
+	if runtime.GOOS == "windows" {
+		cmd := exec.Command("ssh", args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			log.Fatalf("ssh command failed: %v", err)
+		}
+		return
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/bash"
+	}
+	base := filepath.Base(shell)
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	cmd := "exec ssh " + strings.Join(quoted, " ")
+	var argv []string
+	switch base {
+	case "bash", "zsh", "fish":
+		argv = []string{shell, "-l", "-i", "-c", cmd}
+	default:
+		argv = []string{shell, "-i", "-c", cmd}
+	}
+	if err := syscall.Exec(shell, argv, os.Environ()); err != nil {
+		log.Fatalf("failed to exec command: %v", err)
+	}
+}
+
+// sshArgsFor builds the argv (excluding "ssh" itself) to connect to h.
+func sshArgsFor(h shh.Host) ([]string, error) {
+	if !shh.IsSafeHost(h.Host) {
+		return nil, fmt.Errorf("unsafe host %q", h.Host)
+	}
+	if h.User != "" || h.Port != "" || h.ProxyJump != "" {
+		// Structured fields set via the Add/Edit form always win, even over
+		// an ssh_config-imported host: the user explicitly overrode them.
+		return buildSSHArgs(h.Host, h.User, h.Port, h.ProxyJump, ""), nil
+	}
+
+	if h.Source == shh.SourceSSHConfig {
+		// Let ssh resolve the alias against ~/.ssh/config itself, so edits to
+		// the config file (HostName, IdentityFile, ...) take effect without
+		// needing a re-import.
+		return []string{h.Host}, nil
+	}
+
+	var entry shh.SSHConfigEntry
+	if err := json.Unmarshal([]byte(h.Comment), &entry); err != nil || entry.IsEmpty() {
+		return []string{h.Host}, nil
+	}
+
+	target := h.Host
+	if entry.HostName != "" {
+		target = entry.HostName
+	}
+	args := buildSSHArgs(target, entry.User, entry.Port, entry.ProxyJump, entry.IdentityFile)
+	return args, nil
+}
+
+// buildSSHArgs assembles the ssh flags for target (which may already be a
+// "user@host" string) from optional proxyJump, port, and identityFile values.
+func buildSSHArgs(target, user, port, proxyJump, identityFile string) []string {
+	var args []string
+	if proxyJump != "" {
+		args = append(args, "-J", proxyJump)
+	}
+	if port != "" {
+		args = append(args, "-p", port)
+	}
+	if identityFile != "" {
+		args = append(args, "-i", identityFile)
+	}
+	if user != "" {
+		target = user + "@" + target
+	}
+	return append(args, target)
+}
+
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+func cleanupTerminal() {
+	restoreConsoleState()
+	if !isTerminal(os.Stdout) {
+		return
+	}
+	// Reset basic attributes and ensure the cursor is visible.
+	fmt.Fprint(os.Stdout, ansiResetAndShowCursor)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
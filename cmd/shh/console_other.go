@@ -0,0 +1,10 @@
+//go:build !windows
+
+package main
+
+// captureConsoleState and restoreConsoleState only matter on Windows, where
+// raw console mode bits survive a crash and need restoring; real terminals
+// handle that themselves on other platforms.
+func captureConsoleState() {}
+
+func restoreConsoleState() {}
@@ -0,0 +1,274 @@
+package shh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultDashboardInterval is how often the dashboard re-probes hosts when
+// RunOptions.DashboardInterval (or a direct newDashboardState caller) leaves
+// it unset.
+const DefaultDashboardInterval = 5 * time.Second
+
+const maxSparklineSamples = 20
+
+var (
+	tileStyle          = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).Width(30)
+	tileSelectedStyle  = tileStyle.Copy().BorderForeground(lipgloss.Color("10"))
+	tileReachableStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	tileDownStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	tagChipStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
+)
+
+type probeRoundMsg struct {
+	results []ProbeResult
+	at      time.Time
+}
+
+type dashboardTickMsg struct{}
+
+// dashboardState holds everything the live status grid needs beyond the
+// shared list/search/filter state already on Model.
+type dashboardState struct {
+	prober    Prober
+	breaker   *circuitBreaker
+	interval  time.Duration
+	statuses  map[int64]HostStatus
+	cursor    int
+	lastError string
+}
+
+func newDashboardState(interval time.Duration) dashboardState {
+	if interval <= 0 {
+		interval = DefaultDashboardInterval
+	}
+	return dashboardState{
+		prober:   newTCPProber(2 * time.Second),
+		breaker:  newCircuitBreaker(),
+		interval: interval,
+		statuses: map[int64]HostStatus{},
+	}
+}
+
+// enterDashboard switches into dashboard uiMode, seeding it with whatever
+// statuses are already on disk so the grid isn't empty before the first
+// probe round lands.
+// enterDashboard is a no-op when store has no local sqlite backing (e.g. a
+// --connect remote picker): there's nowhere to persist probe history, so the
+// dashboard key is simply ignored.
+func (m Model) enterDashboard() (Model, tea.Cmd) {
+	if m.SQLiteStore == nil {
+		m.status = "dashboard is not available against a remote store"
+		return m, nil
+	}
+	m.uiMode = uiModeDashboard
+	if statuses, err := m.SQLiteStore.GetHostStatuses(m.ctx); err == nil {
+		m.dash.statuses = statuses
+	}
+	m.dash.cursor = 0
+	return m, tea.Batch(m.probeCmd(), m.dashboardTickCmd())
+}
+
+func (m Model) probeCmd() tea.Cmd {
+	hosts := make([]Host, 0, len(m.filteredIx))
+	for _, idx := range m.filteredIx {
+		hosts = append(hosts, m.allHosts[idx])
+	}
+	prober, breaker := m.dash.prober, m.dash.breaker
+	ctx := m.ctx
+	store := m.SQLiteStore
+	return func() tea.Msg {
+		results := probeRound(ctx, prober, breaker, hosts)
+		prev, _ := store.GetHostStatuses(ctx)
+		for _, r := range results {
+			_ = persistProbeResult(ctx, store, prev, r)
+		}
+		return probeRoundMsg{results: results, at: time.Now()}
+	}
+}
+
+func persistProbeResult(ctx context.Context, store *SQLiteStore, prev map[int64]HostStatus, r ProbeResult) error {
+	if r.Err == errCircuitOpen {
+		return nil // breaker-shed hosts don't get a fresh status row
+	}
+	samples := append(prev[r.HostID].Samples, r.RTT.Milliseconds())
+	if len(samples) > maxSparklineSamples {
+		samples = samples[len(samples)-maxSparklineSamples:]
+	}
+	return store.UpsertHostStatus(ctx, HostStatus{
+		HostID:      r.HostID,
+		Reachable:   r.Reachable,
+		Banner:      r.Banner,
+		RTTMillis:   r.RTT.Milliseconds(),
+		LastProbeAt: r.At,
+		Samples:     samples,
+	})
+}
+
+func (m Model) dashboardTickCmd() tea.Cmd {
+	interval := m.dash.interval
+	return tea.Tick(interval, func(time.Time) tea.Msg { return dashboardTickMsg{} })
+}
+
+func (m Model) handleDashboardKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc", "ctrl+b":
+		m.uiMode = uiModeList
+		return m, nil, true
+	case "up", "left":
+		if m.dash.cursor > 0 {
+			m.dash.cursor--
+		}
+		return m, nil, true
+	case "down", "right":
+		if m.dash.cursor < len(m.filteredIx)-1 {
+			m.dash.cursor++
+		}
+		return m, nil, true
+	case "r":
+		return m, m.probeCmd(), true
+	case "enter":
+		if m.dash.cursor >= 0 && m.dash.cursor < len(m.filteredIx) {
+			sel := m.allHosts[m.filteredIx[m.dash.cursor]]
+			if err := m.store.MarkUsed(m.ctx, sel.ID); err != nil {
+				m.status = "mark used: " + err.Error()
+			}
+			m.FinalHost = sel.Host
+			m.FinalRec = sel
+			return m, tea.Quit, true
+		}
+	}
+	return m, nil, false
+}
+
+func (m *Model) applyProbeResults(results []ProbeResult, at time.Time) {
+	for _, r := range results {
+		if r.Err == errCircuitOpen {
+			continue
+		}
+		st := m.dash.statuses[r.HostID]
+		samples := append(st.Samples, r.RTT.Milliseconds())
+		if len(samples) > maxSparklineSamples {
+			samples = samples[len(samples)-maxSparklineSamples:]
+		}
+		m.dash.statuses[r.HostID] = HostStatus{
+			HostID:      r.HostID,
+			Reachable:   r.Reachable,
+			Banner:      r.Banner,
+			RTTMillis:   r.RTT.Milliseconds(),
+			LastProbeAt: at,
+			Samples:     samples,
+		}
+	}
+}
+
+func (m Model) dashboardView() string {
+	if len(m.filteredIx) == 0 {
+		return baseStyle.Render(headerStyle.Render("shh - dashboard") + "\n\nNo hosts match the current filter.\n\n" +
+			statusStyle.Render("Esc/Ctrl+B back  r refresh  Enter connect  Ctrl+C quit"))
+	}
+
+	tileWidth := tileStyle.GetWidth() + 2
+	cols := max(1, m.width/tileWidth)
+
+	var tiles []string
+	for i, idx := range m.filteredIx {
+		h := m.allHosts[idx]
+		tiles = append(tiles, m.renderTile(h, i == m.dash.cursor))
+	}
+
+	var rows []string
+	for i := 0; i < len(tiles); i += cols {
+		end := min(i+cols, len(tiles))
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, tiles[i:end]...))
+	}
+	grid := lipgloss.JoinVertical(lipgloss.Left, rows...)
+
+	footer := statusStyle.Render(fmt.Sprintf("Probing every %s  Esc/Ctrl+B back  r refresh  Enter connect  Ctrl+C quit", m.dash.interval))
+	return baseStyle.Render(headerStyle.Render("shh - dashboard") + "\n\n" + grid + "\n" + footer)
+}
+
+func (m Model) renderTile(h Host, selected bool) string {
+	st, known := m.dash.statuses[h.ID]
+
+	reach := "? unknown"
+	reachStyle := statusStyle
+	if known {
+		if st.Reachable {
+			reach, reachStyle = "up", tileReachableStyle
+		} else {
+			reach, reachStyle = "down", tileDownStyle
+		}
+	}
+
+	lines := []string{
+		headerStyle.Render(h.Host),
+	}
+	if len(h.Tags) > 0 {
+		lines = append(lines, tagChipStyle.Render(renderTagChips(h.Tags)))
+	}
+	lines = append(lines, reachStyle.Render(reach))
+	if known {
+		banner := st.Banner
+		if banner == "" {
+			banner = "-"
+		}
+		lines = append(lines, fmt.Sprintf("banner: %s", truncate(banner, 24)))
+		lines = append(lines, fmt.Sprintf("rtt: %dms", st.RTTMillis))
+		lines = append(lines, sparkline(st.Samples))
+	}
+
+	style := tileStyle
+	if selected {
+		style = tileSelectedStyle
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+func renderTagChips(tags []string) string {
+	chips := make([]string, len(tags))
+	for i, t := range tags {
+		chips[i] = "#" + t
+	}
+	return strings.Join(chips, " ")
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func sparkline(samples []int64) string {
+	if len(samples) == 0 {
+		return ""
+	}
+	var lo, hi int64 = samples[0], samples[0]
+	for _, v := range samples {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	span := hi - lo
+	out := make([]rune, len(samples))
+	for i, v := range samples {
+		if span == 0 {
+			out[i] = sparkBlocks[0]
+			continue
+		}
+		level := int((v - lo) * int64(len(sparkBlocks)-1) / span)
+		out[i] = sparkBlocks[level]
+	}
+	return string(out)
+}
@@ -1,4 +1,4 @@
-package main
+package shh
 
 import (
 	"bufio"
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
@@ -36,7 +38,10 @@ var sshOptionsWithArg = map[string]bool{
 	"-w": true,
 }
 
-func (s *Store) ImportFromHistory(ctx context.Context) (int, error) {
+func (s *SQLiteStore) ImportFromHistory(ctx context.Context) (imported int, err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.ImportFromHistory")
+	defer func() { endSpan(span, err) }()
+
 	paths := possibleHistoryFiles()
 	if len(paths) == 0 {
 		return 0, nil
@@ -44,7 +49,6 @@ func (s *Store) ImportFromHistory(ctx context.Context) (int, error) {
 
 	var errs []error
 	seen := map[string]struct{}{}
-	imported := 0
 
 	for _, p := range paths {
 		fileImported, err := s.importHistoryFile(ctx, p, seen)
@@ -55,10 +59,12 @@ func (s *Store) ImportFromHistory(ctx context.Context) (int, error) {
 		imported += fileImported
 	}
 
-	return imported, errors.Join(errs...)
+	span.SetAttributes(attribute.Int("shh.row_count", imported))
+	err = errors.Join(errs...)
+	return imported, err
 }
 
-func (s *Store) importHistoryFile(ctx context.Context, path string, seen map[string]struct{}) (int, error) {
+func (s *SQLiteStore) importHistoryFile(ctx context.Context, path string, seen map[string]struct{}) (int, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		// quietly skip missing files
@@ -191,7 +197,7 @@ func parseHistoryLine(line string) (string, bool) {
 		if host == "" {
 			return "", false
 		}
-		norm, err := normalizeHost(host)
+		norm, err := NormalizeHost(host)
 		if err != nil {
 			return "", false
 		}
@@ -0,0 +1,92 @@
+package shh
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPStore is a Store backed by a remote "shh --serve" instance, used by
+// --connect so the same picker can run against a host someone else is
+// already running shh on, instead of the local sqlite file.
+type HTTPStore struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewHTTPStore(baseURL, token string) *HTTPStore {
+	return &HTTPStore{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *HTTPStore) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (s *HTTPStore) ListHosts(ctx context.Context) ([]Host, error) {
+	var hosts []Host
+	err := s.do(ctx, http.MethodGet, "/api/hosts", nil, &hosts)
+	return hosts, err
+}
+
+func (s *HTTPStore) AddHost(ctx context.Context, spec HostSpec) (int64, error) {
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/api/hosts", spec, &out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+func (s *HTTPStore) UpdateHost(ctx context.Context, id int64, spec HostSpec) error {
+	return s.do(ctx, http.MethodPut, "/api/hosts/"+strconv.FormatInt(id, 10), spec, nil)
+}
+
+func (s *HTTPStore) DeleteHost(ctx context.Context, id int64) error {
+	return s.do(ctx, http.MethodDelete, "/api/hosts/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+func (s *HTTPStore) MarkUsed(ctx context.Context, id int64) error {
+	return s.do(ctx, http.MethodPost, "/api/hosts/"+strconv.FormatInt(id, 10)+"/use", nil, nil)
+}
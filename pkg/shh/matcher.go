@@ -0,0 +1,191 @@
+package shh
+
+import (
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// Match is one item's fuzzy-match result against a query. Positions are
+// byte offsets into the matched item that contributed to Score, so callers
+// can highlight exactly which characters matched.
+type Match struct {
+	Index     int
+	Score     int
+	Positions []int
+}
+
+// Matcher scores items against a query and returns matches in no
+// particular order; callers sort on Score themselves.
+type Matcher interface {
+	Match(query string, items []string) []Match
+}
+
+// NewMatcher resolves a matcher name (as taken from e.g. a --matcher flag)
+// to a Matcher implementation. "" and "simple" select the substring matcher.
+func NewMatcher(name string) (Matcher, error) {
+	switch name {
+	case "", "simple":
+		return simpleMatcher{}, nil
+	case "fzf":
+		return fzfMatcher{}, nil
+	default:
+		return nil, errUnknownMatcher(name)
+	}
+}
+
+type errUnknownMatcher string
+
+func (e errUnknownMatcher) Error() string {
+	return "unknown matcher " + string(e) + " (want simple or fzf)"
+}
+
+// simpleMatcher is the original subsequence matcher, backed by sahilm/fuzzy.
+type simpleMatcher struct{}
+
+func (simpleMatcher) Match(query string, items []string) []Match {
+	results := fuzzy.Find(strings.ToLower(query), items)
+	out := make([]Match, len(results))
+	for i, r := range results {
+		out[i] = Match{Index: r.Index, Score: r.Score, Positions: append([]int(nil), r.MatchedIndexes...)}
+	}
+	return out
+}
+
+// fzfMatcher is a Smith-Waterman-style DP matcher in the spirit of fzf's v2
+// algorithm: consecutive matches and matches on word boundaries score bonus
+// points, while skipped haystack characters ("gaps") cost a penalty that's
+// steeper for the first skipped character than for a run of them.
+type fzfMatcher struct{}
+
+const (
+	fzfScoreMatch       = 16
+	fzfBonusBoundary    = 8
+	fzfBonusConsecutive = 4
+	fzfBonusFirstChar   = 2
+	fzfPenaltyGapStart  = -3
+	fzfPenaltyGapExtend = -1
+	fzfMinScore         = -1 << 30
+)
+
+func (fzfMatcher) Match(query string, items []string) []Match {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		out := make([]Match, len(items))
+		for i := range items {
+			out[i] = Match{Index: i}
+		}
+		return out
+	}
+	var out []Match
+	for i, item := range items {
+		if score, positions, ok := fzfScore(q, item); ok {
+			out = append(out, Match{Index: i, Score: score, Positions: positions})
+		}
+	}
+	return out
+}
+
+// fzfScore aligns pattern p (already lower-cased) against haystack h,
+// comparing case-insensitively, and returns the best score together with
+// the h byte offsets that contributed to it, recovered by backtracking
+// through the DP table.
+func fzfScore(p, h string) (int, []int, bool) {
+	hl := strings.ToLower(h)
+	n, m := len(hl), len(p)
+	if n == 0 || m == 0 || m > n {
+		return 0, nil, false
+	}
+
+	// score[i][j]: best score aligning p[:j] into h[:i], ending with p[j-1]
+	// matched at h[i-1]. gapRun[i][j]: length of the open run of skipped
+	// haystack characters leading into that cell (0 right after a match),
+	// so only the first skip in a run pays the steeper gap-open penalty.
+	score := make([][]int, n+1)
+	gapRun := make([][]int, n+1)
+	for i := range score {
+		score[i] = make([]int, m+1)
+		gapRun[i] = make([]int, m+1)
+	}
+
+	for j := 1; j <= m; j++ {
+		for i := j; i <= n; i++ {
+			best := fzfMinScore
+			bestGap := 0
+			if hl[i-1] == p[j-1] {
+				// consecutive: the previous pattern char's best alignment ended
+				// in a match (no open gap), so this match directly follows it.
+				consecutive := j > 1 && gapRun[i-1][j-1] == 0
+				bonus := fzfScoreMatch
+				if isWordBoundary(h, i-1) {
+					bonus += fzfBonusBoundary
+				}
+				if consecutive {
+					bonus += fzfBonusConsecutive
+				}
+				if i == 1 {
+					bonus += fzfBonusFirstChar
+				}
+				prev := 0
+				if j > 1 {
+					prev = score[i-1][j-1]
+				}
+				best = prev + bonus
+			}
+			if i > j {
+				penalty := fzfPenaltyGapExtend
+				if gapRun[i-1][j] == 0 {
+					penalty = fzfPenaltyGapStart
+				}
+				if cand := score[i-1][j] + penalty; cand > best {
+					best = cand
+					bestGap = gapRun[i-1][j] + 1
+				}
+			}
+			score[i][j] = best
+			gapRun[i][j] = bestGap
+		}
+	}
+
+	best, bestI := fzfMinScore, -1
+	for i := m; i <= n; i++ {
+		if score[i][m] > best {
+			best = score[i][m]
+			bestI = i
+		}
+	}
+	if bestI < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, 0, m)
+	i, j := bestI, m
+	for j > 0 {
+		if gapRun[i][j] == 0 && hl[i-1] == p[j-1] {
+			positions = append(positions, i-1)
+			i--
+			j--
+		} else {
+			i--
+		}
+	}
+	for l, r := 0, len(positions)-1; l < r; l, r = l+1, r-1 {
+		positions[l], positions[r] = positions[r], positions[l]
+	}
+	return best, positions, true
+}
+
+// isWordBoundary reports whether position i in s starts a new "word": the
+// very start of the string, right after a separator, or a lower-to-upper
+// case transition.
+func isWordBoundary(s string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch s[i-1] {
+	case '.', '_', '-', '/', ' ':
+		return true
+	}
+	prev, cur := s[i-1], s[i]
+	return prev >= 'a' && prev <= 'z' && cur >= 'A' && cur <= 'Z'
+}
@@ -0,0 +1,111 @@
+package shh
+
+import "testing"
+
+func TestNewMatcher(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"", false},
+		{"simple", false},
+		{"fzf", false},
+		{"bogus", true},
+	}
+	for _, c := range cases {
+		m, err := NewMatcher(c.name)
+		if c.wantErr {
+			if err == nil {
+				t.Fatalf("NewMatcher(%q): want error, got nil", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NewMatcher(%q): %v", c.name, err)
+		}
+		if m == nil {
+			t.Fatalf("NewMatcher(%q): want non-nil matcher", c.name)
+		}
+	}
+}
+
+func TestSimpleMatcherMatch(t *testing.T) {
+	t.Parallel()
+	items := []string{"prod-db.example.com", "staging-web.example.com", "unrelated"}
+	matches := simpleMatcher{}.Match("prod", items)
+	if len(matches) != 1 || matches[0].Index != 0 {
+		t.Fatalf("Match = %+v, want a single match on index 0", matches)
+	}
+}
+
+func TestFzfMatcherScoresWordBoundaries(t *testing.T) {
+	t.Parallel()
+	items := []string{"prod-db.example.com", "approved.example.com"}
+	matches := fzfMatcher{}.Match("pd", items)
+	if len(matches) != 2 {
+		t.Fatalf("Match returned %d results, want 2", len(matches))
+	}
+	byIndex := map[int]Match{}
+	for _, m := range matches {
+		byIndex[m.Index] = m
+	}
+	// "pd" hits a word boundary on both letters in "prod-db" ("p" starts the
+	// string, "d" follows the "-") but only the interior "p" in "approved".
+	if byIndex[0].Score <= byIndex[1].Score {
+		t.Fatalf("expected prod-db (%d) to outscore approved (%d)", byIndex[0].Score, byIndex[1].Score)
+	}
+}
+
+func TestFzfMatcherNoMatch(t *testing.T) {
+	t.Parallel()
+	matches := fzfMatcher{}.Match("xyz", []string{"example.com"})
+	if len(matches) != 0 {
+		t.Fatalf("Match = %+v, want no matches", matches)
+	}
+}
+
+func TestFzfMatcherEmptyQueryMatchesEverything(t *testing.T) {
+	t.Parallel()
+	items := []string{"a", "b", "c"}
+	matches := fzfMatcher{}.Match("", items)
+	if len(matches) != len(items) {
+		t.Fatalf("Match returned %d results, want %d", len(matches), len(items))
+	}
+}
+
+func TestFzfScorePositionsCoverQuery(t *testing.T) {
+	t.Parallel()
+	score, positions, ok := fzfScore("db", "prod-db.example.com")
+	if !ok {
+		t.Fatalf("fzfScore: want a match")
+	}
+	if len(positions) != 2 {
+		t.Fatalf("positions = %v, want 2 entries", positions)
+	}
+	if score <= 0 {
+		t.Fatalf("score = %d, want positive", score)
+	}
+	for i, p := range positions {
+		if p < 0 || p >= len("prod-db.example.com") {
+			t.Fatalf("positions[%d] = %d out of range", i, p)
+		}
+	}
+}
+
+func TestIsWordBoundary(t *testing.T) {
+	t.Parallel()
+	s := "prod-Db.host"
+	if !isWordBoundary(s, 0) {
+		t.Fatalf("index 0 should be a boundary")
+	}
+	if !isWordBoundary(s, 5) {
+		t.Fatalf("index 5 (after '-') should be a boundary")
+	}
+	if !isWordBoundary(s, 8) {
+		t.Fatalf("index 8 (after '.') should be a boundary")
+	}
+	if isWordBoundary(s, 2) {
+		t.Fatalf("index 2 (mid-word) should not be a boundary")
+	}
+}
@@ -0,0 +1,109 @@
+package shh
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Meta keys recording one-time startup imports, shared between Open's
+// caller (which decides whether to run an import) and the SQLiteStore's
+// own GetMeta/SetMeta.
+const (
+	ImportDoneKey    = "import_done"
+	ImportSSHDoneKey = "import_ssh_done"
+)
+
+// Open opens (creating if necessary) the sqlite database at path, ensures
+// its schema is up to date, and returns a ready-to-use *SQLiteStore.
+func Open(path string) (*SQLiteStore, error) {
+	db, err := sql.Open(sqlDriverName, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return NewSQLiteStore(db), nil
+}
+
+func ensureSchema(db *sql.DB) error {
+	stmts := []string{
+		`PRAGMA journal_mode=WAL;`,
+		`CREATE TABLE IF NOT EXISTS hosts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			host TEXT NOT NULL UNIQUE,
+			comment TEXT,
+			source TEXT NOT NULL DEFAULT 'manual',
+			last_used_at TIMESTAMP NULL,
+			use_count INTEGER NOT NULL DEFAULT 0
+		);`,
+		`CREATE TABLE IF NOT EXISTS meta (
+			key TEXT PRIMARY KEY,
+			value TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS host_tags (
+			host_id INTEGER NOT NULL REFERENCES hosts(id) ON DELETE CASCADE,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (host_id, tag)
+		);`,
+		`CREATE TABLE IF NOT EXISTS host_status (
+			host_id INTEGER PRIMARY KEY REFERENCES hosts(id) ON DELETE CASCADE,
+			reachable INTEGER NOT NULL DEFAULT 0,
+			banner TEXT,
+			rtt_ms INTEGER NOT NULL DEFAULT 0,
+			last_probe_at TIMESTAMP,
+			samples TEXT
+		);`,
+	}
+	for _, s := range stmts {
+		if _, err := db.Exec(s); err != nil {
+			return err
+		}
+	}
+	if err := addColumnIfMissing(db, "hosts", "source", `TEXT NOT NULL DEFAULT 'manual'`); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "hosts", "user", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "hosts", "port", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "hosts", "proxy_jump", `TEXT NOT NULL DEFAULT ''`); err != nil {
+		return err
+	}
+	return nil
+}
+
+// addColumnIfMissing adds col to table if it isn't already present. SQLite has
+// no ADD COLUMN IF NOT EXISTS portable across the versions we support, so we
+// check pragma table_info first.
+func addColumnIfMissing(db *sql.DB, table, col, def string) error {
+	rows, err := db.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			ctype     string
+			notnull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == col {
+			return rows.Err()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, col, def))
+	return err
+}
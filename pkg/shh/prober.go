@@ -0,0 +1,219 @@
+package shh
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxProbeWorkers bounds the probe worker pool regardless of how many CPUs
+// the host machine reports.
+const maxProbeWorkers = 32
+
+const defaultSSHPort = "22"
+
+// ProbeResult is what a single Probe call reports back for one host.
+type ProbeResult struct {
+	HostID    int64
+	Host      string
+	Reachable bool
+	Banner    string
+	RTT       time.Duration
+	Err       error
+	At        time.Time
+}
+
+// Prober checks whether a single host is reachable. Implementations must be
+// safe for concurrent use.
+type Prober interface {
+	Probe(ctx context.Context, h Host) ProbeResult
+}
+
+// tcpProber dials port 22 (or the port recorded in the host's ssh_config
+// metadata) and reads back whatever SSH banner the server offers.
+type tcpProber struct {
+	Timeout time.Duration
+}
+
+func newTCPProber(timeout time.Duration) *tcpProber {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &tcpProber{Timeout: timeout}
+}
+
+func (p *tcpProber) Probe(ctx context.Context, h Host) ProbeResult {
+	res := ProbeResult{HostID: h.ID, Host: h.Host, At: time.Now()}
+
+	target, port := probeTarget(h)
+	addr := net.JoinHostPort(target, port)
+
+	dialer := net.Dialer{Timeout: p.Timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer conn.Close()
+	res.RTT = time.Since(start)
+	res.Reachable = true
+
+	_ = conn.SetReadDeadline(time.Now().Add(p.Timeout))
+	banner, _ := bufio.NewReader(conn).ReadString('\n')
+	res.Banner = trimBanner(banner)
+	return res
+}
+
+func trimBanner(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// probeTarget resolves the dial target and port for h, preferring whatever
+// ssh_config metadata we have on file over the bare alias.
+func probeTarget(h Host) (target, port string) {
+	target, port = h.Host, defaultSSHPort
+	if h.Source != SourceSSHConfig {
+		return target, port
+	}
+	var entry SSHConfigEntry
+	if err := json.Unmarshal([]byte(h.Comment), &entry); err != nil {
+		return target, port
+	}
+	if entry.HostName != "" {
+		target = entry.HostName
+	}
+	if entry.Port != "" {
+		if _, err := strconv.Atoi(entry.Port); err == nil {
+			port = entry.Port
+		}
+	}
+	return target, port
+}
+
+// proberPoolSize returns a worker count sized by the machine's CPU count,
+// clamped to something that won't open hundreds of sockets at once.
+func proberPoolSize(hostCount int) int {
+	n := runtime.NumCPU()
+	if n < 1 {
+		n = 1
+	}
+	if n > maxProbeWorkers {
+		n = maxProbeWorkers
+	}
+	if hostCount > 0 && n > hostCount {
+		n = hostCount
+	}
+	return n
+}
+
+// errCircuitOpen is reported for hosts the breaker is currently shedding.
+var errCircuitOpen = errors.New("circuit open: skipping probe after repeated failures")
+
+// circuitBreaker keeps an unreachable host from being dialed on every tick.
+// Failures back off exponentially (capped) per host; a single success
+// resets the host back to a clean state.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	failures    map[int64]int
+	nextAttempt map[int64]time.Time
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{
+		failures:    map[int64]int{},
+		nextAttempt: map[int64]time.Time{},
+	}
+}
+
+func (b *circuitBreaker) Allow(hostID int64, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	next, ok := b.nextAttempt[hostID]
+	return !ok || !now.Before(next)
+}
+
+func (b *circuitBreaker) RecordResult(hostID int64, ok bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		delete(b.failures, hostID)
+		delete(b.nextAttempt, hostID)
+		return
+	}
+	b.failures[hostID]++
+	b.nextAttempt[hostID] = now.Add(backoffFor(b.failures[hostID]))
+}
+
+func backoffFor(failures int) time.Duration {
+	const maxBackoff = 2 * time.Minute
+	backoff := time.Duration(1<<uint(min(failures, 6))) * time.Second
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// probeRound fans hosts out across a bounded worker pool and returns one
+// ProbeResult per host, in no particular order. Hosts the breaker is
+// currently shedding are reported with errCircuitOpen instead of being
+// dialed.
+func probeRound(ctx context.Context, prober Prober, breaker *circuitBreaker, hosts []Host) []ProbeResult {
+	if len(hosts) == 0 {
+		return nil
+	}
+	poolSize := proberPoolSize(len(hosts))
+
+	jobs := make(chan Host)
+	results := make(chan ProbeResult, len(hosts))
+
+	var wg sync.WaitGroup
+	for i := 0; i < poolSize; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for h := range jobs {
+				now := time.Now()
+				if !breaker.Allow(h.ID, now) {
+					results <- ProbeResult{HostID: h.ID, Host: h.Host, Err: errCircuitOpen, At: now}
+					continue
+				}
+				res := prober.Probe(ctx, h)
+				breaker.RecordResult(h.ID, res.Reachable, time.Now())
+				results <- res
+			}
+		}()
+	}
+
+	go func() {
+		for _, h := range hosts {
+			jobs <- h
+		}
+		close(jobs)
+	}()
+
+	wg.Wait()
+	close(results)
+
+	out := make([]ProbeResult, 0, len(hosts))
+	for r := range results {
+		out = append(out, r)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package shh
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	table "github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RunOptions configures Run and NewModel for embedding: the zero value runs
+// the picker exactly as the shh CLI does (mode RunExecShell, simple matcher,
+// default columns, dashboard off).
+type RunOptions struct {
+	Mode RunMode
+
+	// InitialQuery pre-fills the search box, as if the user had typed it.
+	InitialQuery string
+
+	// Matcher selects the fuzzy algorithm; nil uses the substring matcher.
+	Matcher Matcher
+
+	// Dashboard starts the picker directly in the live status dashboard.
+	Dashboard         bool
+	DashboardInterval time.Duration
+
+	// Columns overrides the list view's table columns; nil uses the
+	// responsive Host/Comment/Src/Last Used/# layout.
+	Columns []table.Column
+
+	// KeyBindings lets an embedder intercept a key in the list view before
+	// the built-in bindings see it. A binding returns (cmd, true) to take
+	// over the key, or (nil, false) to fall through to the built-ins.
+	KeyBindings map[string]func(*Model) (tea.Cmd, bool)
+
+	// OnSelect, if set, is called once the TUI has exited (terminal already
+	// restored) with the full selected Host record, letting an embedder
+	// drive its own connect/print logic instead of inspecting just the
+	// host name Run returns. This is the "custom action on Enter" hook; it
+	// is skipped if the user quit without selecting a host.
+	OnSelect func(ctx context.Context, host Host) error
+}
+
+// Run drives the picker to completion: it starts a tea.Program, blocks until
+// the user selects a host or quits, and returns the selected host's name (or
+// "" if the user quit without selecting one).
+func Run(ctx context.Context, store Store, opts RunOptions) (string, error) {
+	m, err := newModelWithOptions(ctx, store, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var progOpts []tea.ProgramOption
+	if runtime.GOOS != "windows" {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(m, progOpts...)
+	res, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+	final := res.(Model)
+	if final.FinalHost != "" && opts.OnSelect != nil {
+		if err := opts.OnSelect(ctx, final.FinalRec); err != nil {
+			return final.FinalHost, err
+		}
+	}
+	return final.FinalHost, nil
+}
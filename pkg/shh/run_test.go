@@ -0,0 +1,55 @@
+package shh
+
+import (
+	"context"
+	"testing"
+
+	table "github.com/charmbracelet/bubbles/table"
+)
+
+type fakeStore struct {
+	hosts []Host
+}
+
+func (s *fakeStore) ListHosts(ctx context.Context) ([]Host, error)               { return s.hosts, nil }
+func (s *fakeStore) AddHost(ctx context.Context, spec HostSpec) (int64, error)   { return 0, nil }
+func (s *fakeStore) UpdateHost(ctx context.Context, id int64, spec HostSpec) error { return nil }
+func (s *fakeStore) DeleteHost(ctx context.Context, id int64) error              { return nil }
+func (s *fakeStore) MarkUsed(ctx context.Context, id int64) error               { return nil }
+
+func TestNewModelDefaults(t *testing.T) {
+	t.Parallel()
+	store := &fakeStore{hosts: []Host{{ID: 1, Host: "example.com"}}}
+	m, err := NewModel(context.Background(), store, RunPrintHost)
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	if m.Mode != RunPrintHost {
+		t.Fatalf("Mode = %v, want RunPrintHost", m.Mode)
+	}
+	if _, ok := m.matcher.(simpleMatcher); !ok {
+		t.Fatalf("matcher = %T, want simpleMatcher when none is given", m.matcher)
+	}
+	if len(m.allHosts) != 1 {
+		t.Fatalf("allHosts = %v, want the one host from the store", m.allHosts)
+	}
+}
+
+func TestNewModelWithOptionsAppliesInitialQueryAndColumns(t *testing.T) {
+	t.Parallel()
+	store := &fakeStore{hosts: []Host{{ID: 1, Host: "prod-db"}, {ID: 2, Host: "staging-web"}}}
+	cols := []table.Column{{Title: "Host", Width: 10}}
+	m, err := newModelWithOptions(context.Background(), store, RunOptions{
+		InitialQuery: "prod",
+		Columns:      cols,
+	})
+	if err != nil {
+		t.Fatalf("newModelWithOptions: %v", err)
+	}
+	if len(m.filteredIx) != 1 || m.allHosts[m.filteredIx[0]].Host != "prod-db" {
+		t.Fatalf("filteredIx = %v, want only prod-db matched", m.filteredIx)
+	}
+	if len(m.list.columns) != len(cols) {
+		t.Fatalf("list.columns = %v, want %v from RunOptions.Columns", m.list.columns, cols)
+	}
+}
@@ -0,0 +1,321 @@
+package shh
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// ServeOptions configures the optional local control endpoint started with
+// --serve. It exists so scripts and remote pickers (another shh, started
+// with --connect) can list/add/update/delete hosts without going through the
+// TUI, and so a remote picker can watch changes as they happen.
+type ServeOptions struct {
+	Addr     string
+	Token    string
+	Insecure bool // allow binding a non-loopback address
+}
+
+// changeEvent is broadcast to every websocket subscriber whenever a
+// mutation succeeds, so a remote picker's list stays in sync without
+// polling.
+type changeEvent struct {
+	Type string `json:"type"` // "add", "update", "delete", "use"
+	ID   int64  `json:"id"`
+}
+
+// serverStore serializes every mutation through a single goroutine so
+// concurrent HTTP requests can't race on the underlying *sql.DB the way
+// ad-hoc goroutine-per-request writes could. Reads bypass the queue: sqlite
+// handles concurrent readers fine, and this keeps GETs off the write path.
+type serverStore struct {
+	sqlite *SQLiteStore
+	reqCh  chan func()
+	bus    *eventBus
+}
+
+func newServerStore(sqlite *SQLiteStore) *serverStore {
+	ss := &serverStore{
+		sqlite: sqlite,
+		reqCh:  make(chan func()),
+		bus:    newEventBus(),
+	}
+	go ss.loop()
+	return ss
+}
+
+func (ss *serverStore) loop() {
+	for fn := range ss.reqCh {
+		fn()
+	}
+}
+
+// do runs fn on the writer goroutine and waits for it to finish.
+func (ss *serverStore) do(fn func()) {
+	done := make(chan struct{})
+	ss.reqCh <- func() {
+		fn()
+		close(done)
+	}
+	<-done
+}
+
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[chan changeEvent]struct{}
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: map[chan changeEvent]struct{}{}}
+}
+
+func (b *eventBus) subscribe() chan changeEvent {
+	ch := make(chan changeEvent, 16)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *eventBus) unsubscribe(ch chan changeEvent) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+	close(ch)
+}
+
+func (b *eventBus) publish(ev changeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default: // slow subscriber, drop rather than block the writer
+		}
+	}
+}
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true }, // loopback-only bind makes this safe
+}
+
+// RunServer starts the HTTP+WebSocket control endpoint and blocks until ctx
+// is canceled or the listener fails.
+func RunServer(ctx context.Context, sqlite *SQLiteStore, opts ServeOptions) error {
+	if !opts.Insecure {
+		if err := requireLoopback(opts.Addr); err != nil {
+			return err
+		}
+	}
+
+	token := opts.Token
+	if token == "" {
+		var err error
+		token, err = generateAndPersistToken()
+		if err != nil {
+			return fmt.Errorf("generate token: %w", err)
+		}
+	}
+
+	ss := newServerStore(sqlite)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/hosts", ss.handleHosts(token))
+	mux.HandleFunc("/api/hosts/", ss.handleHost(token))
+	mux.HandleFunc("/api/events", ss.handleEvents(token))
+
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on %q: %w", opts.Addr, err)
+	}
+
+	srv := &http.Server{Handler: mux}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	// ln.Addr() reflects the OS-assigned port when opts.Addr ends in ":0"
+	// (the --serve default), unlike opts.Addr itself.
+	log.Printf("shh: serving on %s (token: %s)", ln.Addr(), token)
+	select {
+	case <-ctx.Done():
+		return srv.Close()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// requireLoopback refuses to bind anything but a loopback address unless the
+// operator passed --serve-insecure: this endpoint has no TLS and a bearer
+// token alone shouldn't be trusted on a shared network.
+func requireLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "" {
+		// "" / ":PORT" binds all interfaces, which is exactly what
+		// --serve-insecure exists to gate.
+		return fmt.Errorf("refusing to bind all interfaces %q without --serve-insecure", addr)
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("resolve %q: %w", host, err)
+	}
+	for _, ip := range ips {
+		if !ip.IsLoopback() {
+			return fmt.Errorf("refusing to bind non-loopback address %q without --serve-insecure", addr)
+		}
+	}
+	return nil
+}
+
+func generateAndPersistToken() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		return token, nil // nowhere safe to persist it; caller must copy it from the log line
+	}
+	path := filepath.Join(dir, "shh.token")
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return token, nil
+	}
+	return token, nil
+}
+
+func authorize(r *http.Request, token string) bool {
+	if r.Method == http.MethodGet {
+		return true
+	}
+	got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return got != "" && got == token
+}
+
+func (ss *serverStore) handleHosts(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(r, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			hosts, err := ss.sqlite.ListHosts(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, hosts)
+		case http.MethodPost:
+			var spec HostSpec
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var id int64
+			var err error
+			ss.do(func() { id, err = ss.sqlite.AddHost(r.Context(), spec) })
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ss.bus.publish(changeEvent{Type: "add", ID: id})
+			writeJSON(w, map[string]int64{"id": id})
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+func (ss *serverStore) handleHost(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(r, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		rest := strings.TrimPrefix(r.URL.Path, "/api/hosts/")
+		idStr, action, _ := strings.Cut(rest, "/")
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			http.Error(w, "bad host id", http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPut && action == "":
+			var spec HostSpec
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ss.do(func() { err = ss.sqlite.UpdateHost(r.Context(), id, spec) })
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ss.bus.publish(changeEvent{Type: "update", ID: id})
+		case r.Method == http.MethodDelete && action == "":
+			ss.do(func() { err = ss.sqlite.DeleteHost(r.Context(), id) })
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			ss.bus.publish(changeEvent{Type: "delete", ID: id})
+		case r.Method == http.MethodPost && action == "use":
+			ss.do(func() { err = ss.sqlite.MarkUsed(r.Context(), id) })
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			ss.bus.publish(changeEvent{Type: "use", ID: id})
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleEvents upgrades to a websocket and streams changeEvents as they're
+// published, so a remote picker can refresh instead of polling /api/hosts.
+func (ss *serverStore) handleEvents(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ") != token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := wsUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ch := ss.bus.subscribe()
+		defer ss.bus.unsubscribe(ch)
+		for ev := range ch {
+			if err := conn.WriteJSON(ev); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
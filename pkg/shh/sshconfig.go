@@ -0,0 +1,312 @@
+package shh
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// SSHConfigEntry is the structured metadata we know about a host discovered
+// via ~/.ssh/config. It's stored JSON-encoded in the hosts.comment column so
+// we don't need a schema migration just to remember it.
+type SSHConfigEntry struct {
+	HostName     string `json:"hostname,omitempty"`
+	User         string `json:"user,omitempty"`
+	Port         string `json:"port,omitempty"`
+	IdentityFile string `json:"identity_file,omitempty"`
+	ProxyJump    string `json:"proxy_jump,omitempty"`
+	Comment      string `json:"comment,omitempty"` // trailing "# ..." on the Host line, if any
+}
+
+func (e SSHConfigEntry) IsEmpty() bool {
+	return e == SSHConfigEntry{}
+}
+
+func homeDir() string {
+	if h, err := os.UserHomeDir(); err == nil && h != "" {
+		return h
+	}
+	if u, err := user.Current(); err == nil && u.HomeDir != "" {
+		return u.HomeDir
+	}
+	return ""
+}
+
+// ImportFromSSHConfig parses ~/.ssh/config (following Include directives)
+// and upserts one row per concrete (non-wildcard) Host alias.
+func (s *SQLiteStore) ImportFromSSHConfig(ctx context.Context) (int, error) {
+	home := homeDir()
+	if home == "" {
+		return 0, fmt.Errorf("cannot determine home directory")
+	}
+	path := filepath.Join(home, ".ssh", "config")
+
+	entries, err := parseSSHConfig(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	n := 0
+	for alias, entry := range entries {
+		blob, err := json.Marshal(entry)
+		if err != nil {
+			return n, err
+		}
+		spec := HostSpec{
+			Host:      alias,
+			Comment:   string(blob),
+			User:      entry.User,
+			Port:      entry.Port,
+			ProxyJump: entry.ProxyJump,
+		}
+		if err := s.ImportHostSpecWithSource(ctx, spec, SourceSSHConfig); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// parseSSHConfig reads path (and any Include targets) and returns the
+// concrete aliases it finds, keyed by alias.
+func parseSSHConfig(path string) (map[string]SSHConfigEntry, error) {
+	entries := map[string]SSHConfigEntry{}
+	if err := parseSSHConfigInto(path, map[string]bool{}, entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func parseSSHConfigInto(path string, visited map[string]bool, entries map[string]SSHConfigEntry) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if visited[abs] {
+		return nil
+	}
+	visited[abs] = true
+
+	f, err := os.Open(path)
+	if err != nil {
+		if len(visited) == 1 {
+			return err
+		}
+		// a Included file that doesn't exist is not fatal
+		return nil
+	}
+	defer f.Close()
+
+	var (
+		current      []string // non-wildcard aliases active for subsequent keys
+		inMatchBlock bool
+	)
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := splitConfigLine(line)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "include":
+			for _, pattern := range strings.Fields(val) {
+				for _, p := range expandIncludePattern(pattern, filepath.Dir(path)) {
+					if err := parseSSHConfigInto(p, visited, entries); err != nil {
+						continue
+					}
+				}
+			}
+		case "host":
+			inMatchBlock = false
+			current = nil
+			patterns, comment := splitInlineComment(val)
+			for _, pattern := range strings.Fields(patterns) {
+				if isWildcardPattern(pattern) {
+					continue // wildcard-only targets aren't connectable, skip
+				}
+				if _, seen := entries[pattern]; !seen {
+					entries[pattern] = SSHConfigEntry{}
+				}
+				current = append(current, pattern)
+			}
+			if comment != "" {
+				setForEach(entries, current, func(e *SSHConfigEntry) { e.Comment = comment })
+			}
+		case "match":
+			// Match blocks are conditional and not supported; skip their body
+			// by clearing the active alias set until the next Host line.
+			inMatchBlock = true
+			current = nil
+		case "hostname":
+			if inMatchBlock {
+				continue
+			}
+			setForEach(entries, current, func(e *SSHConfigEntry) { e.HostName = val })
+		case "user":
+			if inMatchBlock {
+				continue
+			}
+			setForEach(entries, current, func(e *SSHConfigEntry) { e.User = val })
+		case "port":
+			if inMatchBlock {
+				continue
+			}
+			setForEach(entries, current, func(e *SSHConfigEntry) { e.Port = val })
+		case "identityfile":
+			if inMatchBlock {
+				continue
+			}
+			setForEach(entries, current, func(e *SSHConfigEntry) { e.IdentityFile = val })
+		case "proxyjump":
+			if inMatchBlock {
+				continue
+			}
+			setForEach(entries, current, func(e *SSHConfigEntry) { e.ProxyJump = val })
+		}
+	}
+	return scanner.Err()
+}
+
+func setForEach(entries map[string]SSHConfigEntry, aliases []string, f func(*SSHConfigEntry)) {
+	for _, a := range aliases {
+		e := entries[a]
+		f(&e)
+		entries[a] = e
+	}
+}
+
+func splitConfigLine(line string) (key, val string, ok bool) {
+	// ssh config allows "Key Value" or "Key=Value" or "Key = Value".
+	if i := strings.IndexAny(line, " \t="); i >= 0 {
+		key = line[:i]
+		rest := strings.TrimSpace(line[i:])
+		rest = strings.TrimPrefix(rest, "=")
+		val = strings.Trim(strings.TrimSpace(rest), `"`)
+		return key, val, true
+	}
+	return "", "", false
+}
+
+// splitInlineComment separates a trailing "# ..." comment from the rest of
+// an ssh_config directive value. It's a simplification of ssh_config's own
+// rules (which only treat '#' as a comment marker outside quoted strings),
+// but trailing comments on Host lines don't use quotes in practice.
+func splitInlineComment(val string) (rest, comment string) {
+	if i := strings.Index(val, "#"); i >= 0 {
+		return strings.TrimSpace(val[:i]), strings.TrimSpace(val[i+1:])
+	}
+	return val, ""
+}
+
+func isWildcardPattern(p string) bool {
+	return strings.ContainsAny(p, "*?") || p == ""
+}
+
+func expandIncludePattern(pattern, baseDir string) []string {
+	if !filepath.IsAbs(pattern) {
+		home := homeDir()
+		if strings.HasPrefix(pattern, "~/") && home != "" {
+			pattern = filepath.Join(home, pattern[2:])
+		} else {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil
+	}
+	return matches
+}
+
+// ---------------------- known_hosts ----------------------
+
+// ImportFromKnownHosts parses ~/.ssh/known_hosts and upserts one row per
+// concrete (non-wildcard, non-hashed) hostname it can recover.
+func (s *SQLiteStore) ImportFromKnownHosts(ctx context.Context) (int, error) {
+	home := homeDir()
+	if home == "" {
+		return 0, fmt.Errorf("cannot determine home directory")
+	}
+	path := filepath.Join(home, ".ssh", "known_hosts")
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	n := 0
+	seen := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		for _, host := range parseKnownHostsLine(scanner.Text()) {
+			if _, dup := seen[host]; dup {
+				continue
+			}
+			seen[host] = struct{}{}
+			if err := s.ImportHostWithSource(ctx, host, "imported from known_hosts", SourceKnownHosts); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, scanner.Err()
+}
+
+// parseKnownHostsLine returns the concrete, recoverable hostnames on a
+// known_hosts line. Hashed entries (`|1|salt|hash`) can't be reversed, so
+// they're skipped, as are marker-prefixed (@revoked/@cert-authority) lines
+// and wildcard patterns.
+func parseKnownHostsLine(line string) []string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return nil
+	}
+	hostsField := fields[0]
+	if strings.HasPrefix(hostsField, "@") {
+		if len(fields) < 4 {
+			return nil
+		}
+		hostsField = fields[1]
+	}
+	if strings.HasPrefix(hostsField, "|") {
+		return nil // hashed, hostname not recoverable
+	}
+
+	var out []string
+	for _, h := range strings.Split(hostsField, ",") {
+		if isWildcardPattern(h) {
+			continue
+		}
+		h = strings.TrimPrefix(h, "[")
+		if i := strings.Index(h, "]"); i >= 0 {
+			h = h[:i]
+		}
+		if h == "" {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out
+}
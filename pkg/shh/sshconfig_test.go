@@ -0,0 +1,148 @@
+package shh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseSSHConfig(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	includePath := filepath.Join(dir, "extra.conf")
+	writeFile(t, includePath, "Host included\n  HostName 10.0.0.9\n")
+
+	cfg := "Host web-* db-*\n" +
+		"  User deploy\n" +
+		"\n" +
+		"Host bastion # prod jump box\n" +
+		"  HostName bastion.example.com\n" +
+		"  User ops\n" +
+		"  Port 2222\n" +
+		"\n" +
+		"Host app\n" +
+		"  HostName app.internal\n" +
+		"  ProxyJump bastion\n" +
+		"  IdentityFile ~/.ssh/app_ed25519\n" +
+		"\n" +
+		"Match host=*.corp\n" +
+		"  User matched\n" +
+		"\n" +
+		"Include extra.conf\n" +
+		"\n" +
+		"Host *\n" +
+		"  User default\n"
+	path := filepath.Join(dir, "config")
+	writeFile(t, path, cfg)
+
+	entries, err := parseSSHConfig(path)
+	if err != nil {
+		t.Fatalf("parseSSHConfig: %v", err)
+	}
+
+	if _, ok := entries["web-*"]; ok {
+		t.Fatalf("wildcard-only stanza should be skipped, got entry for web-*")
+	}
+	if _, ok := entries["*"]; ok {
+		t.Fatalf("wildcard-only stanza should be skipped, got entry for *")
+	}
+
+	bastion, ok := entries["bastion"]
+	if !ok {
+		t.Fatalf("expected bastion entry")
+	}
+	if bastion.HostName != "bastion.example.com" || bastion.User != "ops" || bastion.Port != "2222" {
+		t.Fatalf("bastion entry = %+v, want HostName/User/Port set", bastion)
+	}
+	if bastion.Comment != "prod jump box" {
+		t.Fatalf("bastion entry = %+v, want Comment %q", bastion, "prod jump box")
+	}
+
+	app, ok := entries["app"]
+	if !ok {
+		t.Fatalf("expected app entry")
+	}
+	if app.ProxyJump != "bastion" || app.IdentityFile != "~/.ssh/app_ed25519" {
+		t.Fatalf("app entry = %+v, want ProxyJump/IdentityFile set", app)
+	}
+
+	if _, ok := entries["matched"]; ok {
+		t.Fatalf("Match block body should not create a host entry")
+	}
+
+	included, ok := entries["included"]
+	if !ok {
+		t.Fatalf("expected Include to be followed and produce an 'included' entry")
+	}
+	if included.HostName != "10.0.0.9" {
+		t.Fatalf("included entry = %+v, want HostName 10.0.0.9", included)
+	}
+}
+
+func TestParseKnownHostsLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{
+			name: "simple",
+			line: "example.com ssh-ed25519 AAAA...",
+			want: []string{"example.com"},
+		},
+		{
+			name: "multiple aliases",
+			line: "host1,host2 ssh-rsa AAAA...",
+			want: []string{"host1", "host2"},
+		},
+		{
+			name: "bracketed with port",
+			line: "[example.com]:2222 ssh-ed25519 AAAA...",
+			want: []string{"example.com"},
+		},
+		{
+			name: "hashed entry skipped",
+			line: "|1|abcd1234|efgh5678= ssh-ed25519 AAAA...",
+			want: nil,
+		},
+		{
+			name: "marker prefixed revoked entry",
+			line: "@revoked example.com ssh-ed25519 AAAA...",
+			want: []string{"example.com"},
+		},
+		{
+			name: "wildcard pattern skipped",
+			line: "*.example.com ssh-ed25519 AAAA...",
+			want: nil,
+		},
+		{
+			name: "comment",
+			line: "# a comment",
+			want: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := parseKnownHostsLine(tc.line)
+			if len(got) != len(tc.want) {
+				t.Fatalf("parseKnownHostsLine(%q) = %v, want %v", tc.line, got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("parseKnownHostsLine(%q) = %v, want %v", tc.line, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
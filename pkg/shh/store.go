@@ -0,0 +1,354 @@
+package shh
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Source records where a host entry came from, so a re-import from one
+// source doesn't clobber rows owned by another.
+const (
+	SourceManual     = "manual"
+	SourceHistory    = "history"
+	SourceSSHConfig  = "ssh_config"
+	SourceKnownHosts = "known_hosts"
+)
+
+type Host struct {
+	ID         int64
+	Host       string
+	Comment    string
+	Source     string
+	Tags       []string
+	User       string
+	Port       string
+	ProxyJump  string
+	LastUsedAt sql.NullTime
+	UseCount   int
+}
+
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(db *sql.DB) *SQLiteStore {
+	return &SQLiteStore{db: db}
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) ListHosts(ctx context.Context) (hosts []Host, err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.ListHosts")
+	defer func() { endSpan(span, err) }()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id,host,comment,source,user,port,proxy_jump,last_used_at,use_count
+		FROM hosts
+		ORDER BY CASE WHEN last_used_at IS NULL THEN 1 ELSE 0 END,
+		         last_used_at DESC,
+		         host ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var h Host
+		if err := rows.Scan(&h.ID, &h.Host, &h.Comment, &h.Source, &h.User, &h.Port, &h.ProxyJump, &h.LastUsedAt, &h.UseCount); err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tagsByHost, err := s.tagsByHostID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hosts {
+		hosts[i].Tags = tagsByHost[hosts[i].ID]
+	}
+	span.SetAttributes(attribute.Int("shh.row_count", len(hosts)))
+	return hosts, nil
+}
+
+func (s *SQLiteStore) tagsByHostID(ctx context.Context) (map[int64][]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT host_id, tag FROM host_tags ORDER BY host_id, tag`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64][]string{}
+	for rows.Next() {
+		var id int64
+		var tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, err
+		}
+		out[id] = append(out[id], tag)
+	}
+	return out, rows.Err()
+}
+
+// SetHostTags replaces the full tag set for a host with tags.
+func (s *SQLiteStore) SetHostTags(ctx context.Context, hostID int64, tags []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM host_tags WHERE host_id=?`, hostID); err != nil {
+		return err
+	}
+	seen := map[string]struct{}{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO host_tags(host_id, tag) VALUES(?,?)`, hostID, tag); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// AddHost validates spec (including resolving ProxyJump against this store's
+// known hosts) and inserts it as a manually-added host.
+func (s *SQLiteStore) AddHost(ctx context.Context, spec HostSpec) (id int64, err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.AddHost")
+	defer func() { endSpan(span, err) }()
+
+	spec, err = NormalizeHostSpec(ctx, s, spec)
+	if err != nil {
+		return 0, err
+	}
+	id, err = s.AddHostSpecWithSource(ctx, spec, SourceManual)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("shh.host_id", id))
+	}
+	return id, err
+}
+
+// AddHostWithSource is a thin wrapper around AddHostSpecWithSource for
+// callers that only have a bare host/comment pair, such as history import.
+func (s *SQLiteStore) AddHostWithSource(ctx context.Context, host, comment, source string) (int64, error) {
+	return s.AddHostSpecWithSource(ctx, HostSpec{Host: host, Comment: comment}, source)
+}
+
+// AddHostSpecWithSource inserts spec as-is under source. Unlike AddHost, it
+// does not re-validate User/Port/ProxyJump: callers that already trust their
+// values (import paths reading from ssh_config) use this directly so a
+// bastion alias that hasn't been imported yet doesn't block the import.
+func (s *SQLiteStore) AddHostSpecWithSource(ctx context.Context, spec HostSpec, source string) (int64, error) {
+	normHost, err := NormalizeHost(spec.Host)
+	if err != nil {
+		return 0, err
+	}
+	if source == "" {
+		source = SourceManual
+	}
+	res, err := s.db.ExecContext(ctx, `INSERT INTO hosts(host,comment,source,user,port,proxy_jump) VALUES(?,?,?,?,?,?)`,
+		normHost, strings.TrimSpace(spec.Comment), source, spec.User, spec.Port, spec.ProxyJump)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// UpdateHost validates spec (including resolving ProxyJump against this
+// store's known hosts) and overwrites the host at id with it.
+func (s *SQLiteStore) UpdateHost(ctx context.Context, id int64, spec HostSpec) (err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.UpdateHost", trace.WithAttributes(attribute.Int64("shh.host_id", id)))
+	defer func() { endSpan(span, err) }()
+
+	spec, err = NormalizeHostSpec(ctx, s, spec)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE hosts SET host=?, comment=?, user=?, port=?, proxy_jump=? WHERE id=?`,
+		spec.Host, spec.Comment, spec.User, spec.Port, spec.ProxyJump, id)
+	return err
+}
+
+// DeleteHost removes the host at id along with its tags and status row.
+// Neither sqlite driver this repo supports enables foreign_keys enforcement
+// by default, so the `ON DELETE CASCADE` in host_tags/host_status's schema
+// is declarative only; this deletes from all three tables explicitly in one
+// transaction rather than relying on it.
+func (s *SQLiteStore) DeleteHost(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.DeleteHost", trace.WithAttributes(attribute.Int64("shh.host_id", id)))
+	defer func() { endSpan(span, err) }()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM host_tags WHERE host_id=?`, id); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM host_status WHERE host_id=?`, id); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM hosts WHERE id=?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) MarkUsed(ctx context.Context, id int64) (err error) {
+	ctx, span := tracer.Start(ctx, "shh.store.MarkUsed", trace.WithAttributes(attribute.Int64("shh.host_id", id)))
+	defer func() { endSpan(span, err) }()
+
+	_, err = s.db.ExecContext(ctx, `UPDATE hosts SET use_count=use_count+1, last_used_at=? WHERE id=?`,
+		time.Now().UTC(), id)
+	return err
+}
+
+func (s *SQLiteStore) GetMeta(ctx context.Context, key string) (string, bool, error) {
+	var v string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM meta WHERE key=?`, key).Scan(&v)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	return v, err == nil, err
+}
+
+func (s *SQLiteStore) SetMeta(ctx context.Context, key, value string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO meta(key,value) VALUES(?,?)
+		ON CONFLICT(key) DO UPDATE SET value=excluded.value`, key, value)
+	return err
+}
+
+func (s *SQLiteStore) ImportHost(ctx context.Context, host, comment string) error {
+	return s.ImportHostWithSource(ctx, host, comment, SourceHistory)
+}
+
+// ImportHostWithSource inserts host if it isn't already known. Existing rows
+// are left untouched so a re-import never clobbers a user-edited comment.
+func (s *SQLiteStore) ImportHostWithSource(ctx context.Context, host, comment, source string) error {
+	return s.ImportHostSpecWithSource(ctx, HostSpec{Host: host, Comment: comment}, source)
+}
+
+// ImportHostSpecWithSource inserts spec if its host isn't already known.
+// Existing rows are left untouched so a re-import never clobbers a
+// user-edited comment.
+func (s *SQLiteStore) ImportHostSpecWithSource(ctx context.Context, spec HostSpec, source string) error {
+	_, err := s.AddHostSpecWithSource(ctx, spec, source)
+	if err == nil {
+		return nil
+	}
+	if isUniqueConstraintError(err) {
+		return nil
+	}
+	return fmt.Errorf("add host %q: %w", spec.Host, err)
+}
+
+// HostStatus is the last known probe result for a host, persisted so the
+// dashboard has something to show before the first probe round completes.
+type HostStatus struct {
+	HostID      int64
+	Reachable   bool
+	Banner      string
+	RTTMillis   int64
+	LastProbeAt time.Time
+	Samples     []int64 // recent RTTs in millis, oldest first
+}
+
+func (s *SQLiteStore) GetHostStatuses(ctx context.Context) (map[int64]HostStatus, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT host_id, reachable, banner, rtt_ms, last_probe_at, samples FROM host_status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[int64]HostStatus{}
+	for rows.Next() {
+		var (
+			st        HostStatus
+			reachable int
+			samples   string
+		)
+		if err := rows.Scan(&st.HostID, &reachable, &st.Banner, &st.RTTMillis, &st.LastProbeAt, &samples); err != nil {
+			return nil, err
+		}
+		st.Reachable = reachable != 0
+		st.Samples = decodeSamples(samples)
+		out[st.HostID] = st
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) UpsertHostStatus(ctx context.Context, st HostStatus) error {
+	reachable := 0
+	if st.Reachable {
+		reachable = 1
+	}
+	_, err := s.db.ExecContext(ctx, `INSERT INTO host_status(host_id, reachable, banner, rtt_ms, last_probe_at, samples)
+		VALUES(?,?,?,?,?,?)
+		ON CONFLICT(host_id) DO UPDATE SET
+			reachable=excluded.reachable,
+			banner=excluded.banner,
+			rtt_ms=excluded.rtt_ms,
+			last_probe_at=excluded.last_probe_at,
+			samples=excluded.samples`,
+		st.HostID, reachable, st.Banner, st.RTTMillis, st.LastProbeAt, encodeSamples(st.Samples))
+	return err
+}
+
+func encodeSamples(samples []int64) string {
+	parts := make([]string, len(samples))
+	for i, v := range samples {
+		parts[i] = fmt.Sprintf("%d", v)
+	}
+	return strings.Join(parts, ",")
+}
+
+func decodeSamples(s string) []int64 {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ",")
+	out := make([]int64, 0, len(fields))
+	for _, f := range fields {
+		var v int64
+		if _, err := fmt.Sscanf(f, "%d", &v); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// isUniqueConstraintError reports whether err came from violating a UNIQUE
+// constraint (i.e. a duplicate host). It checks the driver-specific typed
+// error first (see store_cgo.go / store_purego.go) and falls back to a
+// substring match, since both mattn/go-sqlite3 and modernc.org/sqlite embed
+// SQLite's own "UNIQUE constraint failed" message in their Error() text.
+func isUniqueConstraintError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isUniqueConstraintErrorCode(err) {
+		return true
+	}
+	const uniquePrefix = "UNIQUE constraint failed"
+	return strings.Contains(err.Error(), uniquePrefix)
+}
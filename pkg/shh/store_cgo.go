@@ -0,0 +1,24 @@
+//go:build cgo && !purego
+
+package shh
+
+import (
+	"errors"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// sqlDriverName is the database/sql driver registered for this build. Plain
+// cgo builds use mattn/go-sqlite3; see store_purego.go for the pure-Go
+// alternative used when cross-compiling without a C toolchain.
+const sqlDriverName = "sqlite3"
+
+// isUniqueConstraintErrorCode reports whether err is a UNIQUE constraint
+// violation as reported by mattn/go-sqlite3's typed error.
+func isUniqueConstraintErrorCode(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+//go:build !cgo || purego
+
+package shh
+
+import (
+	"errors"
+
+	"modernc.org/sqlite"
+)
+
+// sqlDriverName is the database/sql driver registered for this build.
+// CGO_ENABLED=0 builds (or an explicit -tags purego) fall back to
+// modernc.org/sqlite, a pure-Go translation of SQLite; see store_cgo.go for
+// the cgo driver used otherwise. This is what lets shh cross-compile to
+// e.g. Windows/ARM without a C toolchain.
+const sqlDriverName = "sqlite"
+
+// SQLITE_CONSTRAINT_UNIQUE, per https://www.sqlite.org/rescode.html#constraint_unique.
+const sqliteConstraintUnique = 2067
+
+// isUniqueConstraintErrorCode reports whether err is a UNIQUE constraint
+// violation as reported by modernc.org/sqlite's typed error.
+func isUniqueConstraintErrorCode(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqliteConstraintUnique
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package shh
+
+import "context"
+
+// Store is the minimal host CRUD surface the TUI needs to run. SQLiteStore
+// implements it against the local database; HTTPStore implements it against
+// a remote "shh --serve" instance, so the picker works the same whether it's
+// reading hosts from disk or over the network.
+type Store interface {
+	ListHosts(ctx context.Context) ([]Host, error)
+	AddHost(ctx context.Context, spec HostSpec) (int64, error)
+	UpdateHost(ctx context.Context, id int64, spec HostSpec) error
+	DeleteHost(ctx context.Context, id int64) error
+	MarkUsed(ctx context.Context, id int64) error
+}
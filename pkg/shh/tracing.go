@@ -0,0 +1,61 @@
+package shh
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer instruments Store operations and TUI lifecycle events. With no
+// TracerProvider registered (the default, when --otlp-endpoint is unset)
+// otel.Tracer returns a no-op tracer, so every span below is free until
+// InitTracing is actually called.
+var tracer = otel.Tracer("github.com/Uri2001/shh/pkg/shh")
+
+// InitTracing configures the global TracerProvider to export spans to an
+// OTLP/gRPC collector at otlpEndpoint (e.g. "localhost:4317"). If
+// otlpEndpoint is empty it does nothing, leaving the no-op tracer in place.
+// The returned shutdown func flushes and closes the exporter; callers should
+// defer it.
+func InitTracing(ctx context.Context, otlpEndpoint string) (shutdown func(context.Context) error, err error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exp, err := otlptracegrpc.New(ctx,
+		otlptracegrpc.WithEndpoint(otlpEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("otlp exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName("shh")))
+	if err != nil {
+		return nil, fmt.Errorf("otlp resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// endSpan records err on span (if non-nil) and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,956 @@
+package shh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	table "github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type uiMode int
+
+const (
+	uiModeList uiMode = iota
+	uiModeAdd
+	uiModeEdit
+	uiModeConfirmDelete
+	uiModeDashboard
+)
+
+const tagQueryPrefix = "tag:"
+
+type RunMode int
+
+const (
+	RunExecShell RunMode = iota
+	RunPrintHost
+	RunPrintCmd
+)
+
+type listView struct {
+	table    table.Model
+	search   textinput.Model
+	pageSize int
+	// columns overrides responsiveColumns when set (see RunOptions.Columns),
+	// so an embedder's custom layout survives terminal resizes.
+	columns []table.Column
+}
+
+type formView struct {
+	inputs []textinput.Model
+}
+
+type confirmView struct {
+	prompt string
+}
+
+type Model struct {
+	ctx context.Context
+	// store is the minimal CRUD surface; it may be backed by a local sqlite
+	// file or a remote shh --serve instance (see httpstore.go).
+	store Store
+	// SQLiteStore is non-nil only when store is backed by a local database.
+	// Features with no remote equivalent (tags, dashboard, import) type-assert
+	// through this field and degrade gracefully when it's nil.
+	SQLiteStore *SQLiteStore
+	uiMode      uiMode
+	Mode        RunMode
+	list        listView
+	form        formView
+	confirm     confirmView
+	status      string
+	allHosts    []Host
+	filteredIx  []int
+	FinalHost   string
+	FinalRec    Host
+	width       int
+	height      int
+	dash        dashboardState
+	matcher     Matcher
+	// matchPositions maps an allHosts index to the matched byte offsets in
+	// "host + ' ' + comment" (lower-cased), for highlighting in the list.
+	matchPositions map[int][]int
+	// keyBindings lets an embedder (see RunOptions) intercept a key in
+	// modeList before the built-in bindings see it.
+	keyBindings map[string]func(*Model) (tea.Cmd, bool)
+}
+
+var (
+	baseStyle           = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("7"))
+	headerStyle         = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	statusStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	matchHighlightStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+)
+
+func newListView(columns []table.Column) listView {
+	search := textinput.New()
+	search.Placeholder = "search (host/comment), / to focus, Esc to clear"
+	search.Prompt = "/ "
+	search.CharLimit = 256
+	search.Focus()
+	search.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	search.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+
+	cols := columns
+	if cols == nil {
+		cols = defaultColumns()
+	}
+	tbl := table.New(table.WithColumns(cols), table.WithHeight(15))
+	padding := lipgloss.NewStyle().Padding(0, 1)
+	styles := table.DefaultStyles()
+	styles.Header = padding.Copy().Bold(true).Foreground(lipgloss.Color("10"))
+	styles.Cell = padding.Copy()
+	styles.Selected = lipgloss.NewStyle().Foreground(lipgloss.Color("15")).Bold(true)
+	tbl.SetStyles(styles)
+
+	l := listView{table: tbl, search: search, columns: columns}
+	l.updatePageSize()
+	return l
+}
+
+func (l *listView) updatePageSize() {
+	l.pageSize = l.table.Height()
+	if l.pageSize <= 0 {
+		l.pageSize = 1
+	}
+}
+
+func (l *listView) applyLayout(width, height int) {
+	if width > 0 {
+		l.search.Width = max(20, width-6)
+		if l.columns != nil {
+			l.table.SetColumns(l.columns)
+		} else {
+			l.table.SetColumns(responsiveColumns(width))
+		}
+	}
+	if height > 0 {
+		tableHeight := height - 7
+		if tableHeight < 5 {
+			tableHeight = 5
+		}
+		l.table.SetHeight(tableHeight)
+	}
+	l.updatePageSize()
+}
+
+func (l *listView) moveCursor(delta int) {
+	if delta < 0 {
+		l.table.MoveUp(-delta)
+	} else if delta > 0 {
+		l.table.MoveDown(delta)
+	}
+}
+
+func (l *listView) movePage(delta int) {
+	step := l.pageSize
+	if step <= 0 {
+		step = 1
+	}
+	if delta < 0 {
+		l.table.MoveUp(-delta * step)
+	} else if delta > 0 {
+		l.table.MoveDown(delta * step)
+	}
+}
+
+const (
+	formFieldHost = iota
+	formFieldUser
+	formFieldPort
+	formFieldProxyJump
+	formFieldComment
+	formFieldTags
+	formFieldCount
+)
+
+func newFormView() formView {
+	inputs := make([]textinput.Model, formFieldCount)
+	for i := range inputs {
+		inputs[i] = textinput.New()
+	}
+	inputs[formFieldHost].Placeholder = "example.com"
+	inputs[formFieldHost].CharLimit = 256
+	inputs[formFieldHost].Focus()
+	inputs[formFieldUser].Placeholder = "login user (optional)"
+	inputs[formFieldUser].CharLimit = 64
+	inputs[formFieldPort].Placeholder = "port (optional)"
+	inputs[formFieldPort].CharLimit = 5
+	inputs[formFieldProxyJump].Placeholder = "bastion1,bastion2 (optional)"
+	inputs[formFieldProxyJump].CharLimit = 256
+	inputs[formFieldComment].Placeholder = "description (optional)"
+	inputs[formFieldComment].CharLimit = 512
+	inputs[formFieldTags].Placeholder = "tags, comma-separated (optional)"
+	inputs[formFieldTags].CharLimit = 256
+	return formView{inputs: inputs}
+}
+
+func (f *formView) setHost(h Host) {
+	if len(f.inputs) != formFieldCount {
+		return
+	}
+	f.inputs[formFieldHost].SetValue(h.Host)
+	f.inputs[formFieldHost].Focus()
+	f.inputs[formFieldUser].Blur()
+	f.inputs[formFieldUser].SetValue(h.User)
+	f.inputs[formFieldPort].Blur()
+	f.inputs[formFieldPort].SetValue(h.Port)
+	f.inputs[formFieldProxyJump].Blur()
+	f.inputs[formFieldProxyJump].SetValue(h.ProxyJump)
+	f.inputs[formFieldComment].Blur()
+	f.inputs[formFieldComment].SetValue(h.Comment)
+	f.inputs[formFieldTags].Blur()
+	f.inputs[formFieldTags].SetValue(strings.Join(h.Tags, ", "))
+}
+
+// parseTags splits a comma-separated tags input into a deduplicated,
+// lower-cased slice.
+func parseTags(raw string) []string {
+	var tags []string
+	seen := map[string]struct{}{}
+	for _, part := range strings.Split(raw, ",") {
+		tag := strings.ToLower(strings.TrimSpace(part))
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (f *formView) updateInputs(msg tea.Msg) []tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(f.inputs))
+	for i := range f.inputs {
+		var cmd tea.Cmd
+		f.inputs[i], cmd = f.inputs[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// NewModel builds a Model ready to hand to a tea.Program, e.g. to embed the
+// picker inside a larger Bubble Tea application. Most callers that just want
+// to run the picker to completion should use Run instead.
+func NewModel(ctx context.Context, store Store, mode RunMode) (Model, error) {
+	return newModelWithOptions(ctx, store, RunOptions{Mode: mode})
+}
+
+func newModelWithOptions(ctx context.Context, store Store, opts RunOptions) (Model, error) {
+	ss, _ := store.(*SQLiteStore)
+	matcher := opts.Matcher
+	if matcher == nil {
+		matcher = simpleMatcher{}
+	}
+	m := Model{
+		ctx:         ctx,
+		store:       store,
+		SQLiteStore: ss,
+		uiMode:      uiModeList,
+		Mode:        opts.Mode,
+		list:        newListView(opts.Columns),
+		form:        newFormView(),
+		dash:        newDashboardState(opts.DashboardInterval),
+		matcher:     matcher,
+		keyBindings: opts.KeyBindings,
+	}
+	if err := m.reload(); err != nil {
+		return m, err
+	}
+	if opts.InitialQuery != "" {
+		m.list.search.SetValue(opts.InitialQuery)
+		m.applyFilter(ctx, true)
+	}
+	if opts.Dashboard {
+		m, _ = m.enterDashboard()
+	}
+	return m, nil
+}
+
+func (m *Model) reload() (err error) {
+	ctx, span := tracer.Start(m.ctx, "shh.ui.reload")
+	defer func() { endSpan(span, err) }()
+
+	hosts, err := m.store.ListHosts(ctx)
+	if err != nil {
+		return err
+	}
+	m.allHosts = hosts
+	span.SetAttributes(attribute.Int("shh.row_count", len(hosts)))
+	m.applyFilter(ctx, false)
+	return nil
+}
+
+// modeName returns the tracing-friendly name for a uiMode.
+func modeName(mode uiMode) string {
+	switch mode {
+	case uiModeList:
+		return "list"
+	case uiModeAdd:
+		return "add"
+	case uiModeEdit:
+		return "edit"
+	case uiModeConfirmDelete:
+		return "confirm_delete"
+	case uiModeDashboard:
+		return "dashboard"
+	default:
+		return "unknown"
+	}
+}
+
+// traceModeTransition records a zero-duration span marking a uiMode change,
+// so trace backends can line up e.g. "add -> list" with how long the
+// operator spent in the add form.
+func traceModeTransition(ctx context.Context, from, to uiMode) {
+	_, span := tracer.Start(ctx, "shh.ui.mode_transition", trace.WithAttributes(
+		attribute.String("shh.from_mode", modeName(from)),
+		attribute.String("shh.to_mode", modeName(to)),
+	))
+	span.End()
+}
+
+// importAllSources refreshes hosts from history, ssh_config, and
+// known_hosts. Existing rows are left alone (see ImportHostWithSource), so
+// this is safe to call repeatedly without clobbering user edits.
+func (m *Model) importAllSources() (int, error) {
+	if m.SQLiteStore == nil {
+		return 0, fmt.Errorf("import is not available against a remote store")
+	}
+	total := 0
+	if n, err := m.SQLiteStore.ImportFromHistory(m.ctx); err != nil {
+		return total, fmt.Errorf("history: %w", err)
+	} else {
+		total += n
+	}
+	if n, err := m.SQLiteStore.ImportFromSSHConfig(m.ctx); err != nil {
+		return total, fmt.Errorf("ssh_config: %w", err)
+	} else {
+		total += n
+	}
+	if n, err := m.SQLiteStore.ImportFromKnownHosts(m.ctx); err != nil {
+		return total, fmt.Errorf("known_hosts: %w", err)
+	} else {
+		total += n
+	}
+	if err := m.SQLiteStore.SetMeta(m.ctx, ImportDoneKey, "1"); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+func (m *Model) applyFilter(ctx context.Context, resetCursor bool) {
+	_, span := tracer.Start(ctx, "shh.ui.apply_filter")
+	defer span.End()
+
+	prevCursor := m.list.table.Cursor()
+	query := strings.TrimSpace(m.list.search.Value())
+	m.filteredIx = m.matchingIndices(query)
+	span.SetAttributes(
+		attribute.Int("shh.query_len", len(query)),
+		attribute.Int("shh.match_count", len(m.filteredIx)),
+	)
+	rows := make([]table.Row, 0, len(m.filteredIx))
+	for _, idx := range m.filteredIx {
+		h := m.allHosts[idx]
+		last := "-"
+		if h.LastUsedAt.Valid {
+			last = h.LastUsedAt.Time.Local().Format("2006-01-02 15:04")
+		}
+		hostCell, commentCell := h.Host, displayComment(h)
+		if positions := m.matchPositions[idx]; len(positions) > 0 {
+			hostPos, commentPos := splitMatchPositions(h.Host, positions)
+			hostCell = highlightString(h.Host, hostPos)
+			// Structured ssh_config comments are summarized for display, so the
+			// raw-comment offsets we matched against no longer line up; leave
+			// those plain rather than highlight the wrong characters.
+			if h.Source != SourceSSHConfig {
+				commentCell = highlightString(h.Comment, commentPos)
+			}
+		}
+		row := table.Row{hostCell, commentCell, sourceLabel(h.Source), last, fmt.Sprintf("%d", h.UseCount)}
+		if n := len(m.list.columns); n > 0 && n < len(row) {
+			row = row[:n]
+		}
+		rows = append(rows, row)
+	}
+	m.list.table.SetRows(rows)
+	if len(rows) == 0 {
+		return
+	}
+	if resetCursor {
+		m.list.table.SetCursor(0)
+		return
+	}
+	if prevCursor < 0 {
+		prevCursor = 0
+	}
+	if prevCursor >= len(rows) {
+		prevCursor = len(rows) - 1
+	}
+	m.list.table.SetCursor(prevCursor)
+}
+
+func (m *Model) matchingIndices(query string) []int {
+	wantTags, rest := splitTagQuery(query)
+
+	candidates := make([]int, 0, len(m.allHosts))
+	for i, h := range m.allHosts {
+		if hasAllTags(h.Tags, wantTags) {
+			candidates = append(candidates, i)
+		}
+	}
+
+	if rest == "" {
+		m.matchPositions = nil
+		sort.Slice(candidates, func(a, b int) bool {
+			return hostLess(m.allHosts[candidates[a]], m.allHosts[candidates[b]])
+		})
+		return candidates
+	}
+
+	haystack := make([]string, len(candidates))
+	for i, idx := range candidates {
+		h := m.allHosts[idx]
+		haystack[i] = strings.ToLower(h.Host + " " + h.Comment)
+	}
+	matches := m.matcher.Match(rest, haystack)
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score == matches[j].Score {
+			return hostLess(m.allHosts[candidates[matches[i].Index]], m.allHosts[candidates[matches[j].Index]])
+		}
+		return matches[i].Score > matches[j].Score
+	})
+	idx := make([]int, 0, len(matches))
+	positions := make(map[int][]int, len(matches))
+	for _, match := range matches {
+		hostIdx := candidates[match.Index]
+		idx = append(idx, hostIdx)
+		if len(match.Positions) > 0 {
+			positions[hostIdx] = match.Positions
+		}
+	}
+	m.matchPositions = positions
+	return idx
+}
+
+// splitMatchPositions divides byte offsets into the lower-cased
+// "host + ' ' + comment" string built for matching back across the two
+// display columns, so each can be highlighted independently.
+func splitMatchPositions(host string, positions []int) (hostPos, commentPos []int) {
+	sep := len(host)
+	for _, p := range positions {
+		switch {
+		case p < sep:
+			hostPos = append(hostPos, p)
+		case p > sep:
+			commentPos = append(commentPos, p-sep-1)
+		}
+	}
+	return hostPos, commentPos
+}
+
+// highlightString bolds the bytes of s at positions using
+// matchHighlightStyle, leaving everything else untouched.
+func highlightString(s string, positions []int) string {
+	if len(positions) == 0 {
+		return s
+	}
+	want := make(map[int]struct{}, len(positions))
+	for _, p := range positions {
+		if p >= 0 && p < len(s) {
+			want[p] = struct{}{}
+		}
+	}
+	var b strings.Builder
+	for i, r := range s {
+		if _, ok := want[i]; ok {
+			b.WriteString(matchHighlightStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func hostLess(a, b Host) bool {
+	if a.LastUsedAt.Valid && b.LastUsedAt.Valid {
+		return a.LastUsedAt.Time.After(b.LastUsedAt.Time)
+	}
+	if a.LastUsedAt.Valid != b.LastUsedAt.Valid {
+		return a.LastUsedAt.Valid
+	}
+	return a.Host < b.Host
+}
+
+// splitTagQuery pulls every "tag:foo" token out of query, returning the
+// requested tags and whatever free text is left over for fuzzy matching.
+func splitTagQuery(query string) (tags []string, rest string) {
+	var remaining []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(strings.ToLower(field), tagQueryPrefix) {
+			tag := strings.ToLower(strings.TrimPrefix(field, tagQueryPrefix))
+			if tag != "" {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		remaining = append(remaining, field)
+	}
+	return tags, strings.Join(remaining, " ")
+}
+
+func hasAllTags(hostTags, want []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	have := map[string]struct{}{}
+	for _, t := range hostTags {
+		have[t] = struct{}{}
+	}
+	for _, w := range want {
+		if _, ok := have[w]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Model) Init() tea.Cmd {
+	if m.uiMode == uiModeDashboard {
+		return tea.Batch(m.probeCmd(), m.dashboardTickCmd())
+	}
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	prevMode := m.uiMode
+	newModel, cmd := m.updateInner(msg)
+	if nm, ok := newModel.(Model); ok && nm.uiMode != prevMode {
+		traceModeTransition(nm.ctx, prevMode, nm.uiMode)
+	}
+	return newModel, cmd
+}
+
+func (m Model) updateInner(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.applyLayout(msg.Width, msg.Height)
+		return m, nil
+	case probeRoundMsg:
+		m.applyProbeResults(msg.results, msg.at)
+		return m, nil
+	case dashboardTickMsg:
+		if m.uiMode != uiModeDashboard {
+			return m, nil
+		}
+		return m, tea.Batch(m.probeCmd(), m.dashboardTickCmd())
+	case tea.KeyMsg:
+		switch m.uiMode {
+		case uiModeList:
+			var cmd tea.Cmd
+			var handled bool
+			m, cmd, handled = m.handleListKey(msg)
+			if handled {
+				return m, cmd
+			}
+		case uiModeAdd, uiModeEdit:
+			return m.handleFormKey(msg)
+		case uiModeConfirmDelete:
+			return m.handleConfirmKey(msg)
+		case uiModeDashboard:
+			var cmd tea.Cmd
+			var handled bool
+			m, cmd, handled = m.handleDashboardKey(msg)
+			if handled {
+				return m, cmd
+			}
+		}
+	}
+
+	switch m.uiMode {
+	case uiModeList:
+		prev := m.list.search.Value()
+		var searchCmd tea.Cmd
+		m.list.search, searchCmd = m.list.search.Update(msg)
+		if m.list.search.Value() != prev {
+			m.applyFilter(m.ctx, true)
+		}
+		var tableCmd tea.Cmd
+		m.list.table, tableCmd = m.list.table.Update(msg)
+		return m, tea.Batch(searchCmd, tableCmd)
+	case uiModeAdd, uiModeEdit:
+		cmds := m.form.updateInputs(msg)
+		return m, tea.Batch(cmds...)
+	default:
+		return m, nil
+	}
+}
+
+func (m Model) handleListKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	if fn, ok := m.keyBindings[msg.String()]; ok {
+		cmd, handled := fn(&m)
+		if handled {
+			return m, cmd, true
+		}
+	}
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit, true
+	case "/":
+		m.list.search.Focus()
+		return m, nil, true
+	case "esc":
+		m.list.search.SetValue("")
+		m.applyFilter(m.ctx, true)
+		return m, nil, true
+	case "up":
+		if len(m.filteredIx) == 0 {
+			return m, nil, true
+		}
+		m.list.moveCursor(-1)
+		return m, nil, true
+	case "down":
+		if len(m.filteredIx) == 0 {
+			return m, nil, true
+		}
+		m.list.moveCursor(1)
+		return m, nil, true
+	case "pgup":
+		if len(m.filteredIx) == 0 {
+			return m, nil, true
+		}
+		m.list.movePage(-1)
+		return m, nil, true
+	case "pgdown", "pgdn":
+		if len(m.filteredIx) == 0 {
+			return m, nil, true
+		}
+		m.list.movePage(1)
+		return m, nil, true
+	case "enter":
+		if sel, ok := m.currentSelection(); ok {
+			if err := m.store.MarkUsed(m.ctx, sel.ID); err != nil {
+				m.status = "mark used: " + err.Error()
+			} else {
+				m.status = ""
+			}
+			m.FinalHost = sel.Host
+			m.FinalRec = sel
+			return m, tea.Quit, true
+		}
+	case "ctrl+a", "alt+n":
+		m.uiMode = uiModeAdd
+		m.form.setHost(Host{})
+		m.status = ""
+		return m, nil, true
+	case "ctrl+e", "alt+e":
+		if sel, ok := m.currentSelection(); ok {
+			m.uiMode = uiModeEdit
+			m.form.setHost(sel)
+			m.status = ""
+		}
+		return m, nil, true
+	case "ctrl+d", "alt+d":
+		if sel, ok := m.currentSelection(); ok {
+			m.uiMode = uiModeConfirmDelete
+			m.confirm.prompt = fmt.Sprintf("Delete %s? y/N", sel.Host)
+			m.status = ""
+			m.FinalHost = ""
+		}
+		return m, nil, true
+	case "ctrl+r", "alt+r":
+		added, err := m.importAllSources()
+		if err != nil {
+			m.status = "import error: " + err.Error()
+		} else if err := m.reload(); err != nil {
+			m.status = "reload error: " + err.Error()
+		} else {
+			m.status = fmt.Sprintf("Imported: +%d", added)
+		}
+		return m, nil, true
+	case "ctrl+s", "alt+s":
+		if m.SQLiteStore == nil {
+			m.status = "ssh_config import is not available against a remote store"
+			return m, nil, true
+		}
+		n, err := m.SQLiteStore.ImportFromSSHConfig(m.ctx)
+		if err != nil {
+			m.status = "ssh_config import error: " + err.Error()
+		} else if err := m.reload(); err != nil {
+			m.status = "reload error: " + err.Error()
+		} else {
+			_ = m.SQLiteStore.SetMeta(m.ctx, ImportSSHDoneKey, "1")
+			m.status = fmt.Sprintf("Imported from ssh_config: +%d", n)
+		}
+		return m, nil, true
+	case "ctrl+b", "alt+b":
+		m, cmd := m.enterDashboard()
+		return m, cmd, true
+	}
+	return m, nil, false
+}
+
+func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.uiMode = uiModeList
+		m.status = ""
+		return m, nil
+	case "enter":
+		if m.form.inputs[formFieldHost].Focused() {
+			m.form.inputs[formFieldHost].Blur()
+			m.form.inputs[formFieldUser].Focus()
+			return m, nil
+		}
+		if m.form.inputs[formFieldUser].Focused() {
+			m.form.inputs[formFieldUser].Blur()
+			m.form.inputs[formFieldPort].Focus()
+			return m, nil
+		}
+		if m.form.inputs[formFieldPort].Focused() {
+			m.form.inputs[formFieldPort].Blur()
+			m.form.inputs[formFieldProxyJump].Focus()
+			return m, nil
+		}
+		if m.form.inputs[formFieldProxyJump].Focused() {
+			m.form.inputs[formFieldProxyJump].Blur()
+			m.form.inputs[formFieldComment].Focus()
+			return m, nil
+		}
+		if m.form.inputs[formFieldComment].Focused() {
+			m.form.inputs[formFieldComment].Blur()
+			m.form.inputs[formFieldTags].Focus()
+			return m, nil
+		}
+		spec := HostSpec{
+			Host:      m.form.inputs[formFieldHost].Value(),
+			User:      m.form.inputs[formFieldUser].Value(),
+			Port:      m.form.inputs[formFieldPort].Value(),
+			ProxyJump: m.form.inputs[formFieldProxyJump].Value(),
+			Comment:   m.form.inputs[formFieldComment].Value(),
+		}
+		tags := parseTags(m.form.inputs[formFieldTags].Value())
+		var (
+			err    error
+			hostID int64
+		)
+		if m.uiMode == uiModeAdd {
+			hostID, err = m.store.AddHost(m.ctx, spec)
+		} else if sel, ok := m.currentSelection(); ok {
+			hostID = sel.ID
+			err = m.store.UpdateHost(m.ctx, sel.ID, spec)
+		}
+		if err != nil {
+			m.status = "error: " + err.Error()
+			return m, nil
+		}
+		if m.SQLiteStore != nil {
+			if err := m.SQLiteStore.SetHostTags(m.ctx, hostID, tags); err != nil {
+				m.status = "tags error: " + err.Error()
+				return m, nil
+			}
+		}
+		if err := m.reload(); err != nil {
+			m.status = "reload error: " + err.Error()
+			return m, nil
+		}
+		m.uiMode = uiModeList
+		m.status = "saved"
+		return m, nil
+	}
+	cmds := m.form.updateInputs(msg)
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		if sel, ok := m.currentSelection(); ok {
+			if err := m.store.DeleteHost(m.ctx, sel.ID); err != nil {
+				m.status = "delete error: " + err.Error()
+			} else if err := m.reload(); err != nil {
+				m.status = "reload error: " + err.Error()
+			} else {
+				m.status = "deleted"
+			}
+		}
+		m.uiMode = uiModeList
+		return m, nil
+	case "n", "N", "esc", "enter":
+		m.uiMode = uiModeList
+		m.status = ""
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	switch m.uiMode {
+	case uiModeAdd, uiModeEdit:
+		title := "Add host"
+		if m.uiMode == uiModeEdit {
+			title = "Edit host"
+		}
+		return baseStyle.Render(
+			headerStyle.Render(title) + "\n\n" +
+				"Host:       " + m.form.inputs[formFieldHost].View() + "\n" +
+				"User:       " + m.form.inputs[formFieldUser].View() + "\n" +
+				"Port:       " + m.form.inputs[formFieldPort].View() + "\n" +
+				"ProxyJump:  " + m.form.inputs[formFieldProxyJump].View() + "\n" +
+				"Comment:    " + m.form.inputs[formFieldComment].View() + "\n" +
+				"Tags:       " + m.form.inputs[formFieldTags].View() + "\n\n" +
+				statusStyle.Render(m.status+"  (Enter: next/save, Esc: cancel)"),
+		)
+	case uiModeConfirmDelete:
+		return baseStyle.Render(
+			headerStyle.Render("Confirm") + "\n\n" +
+				statusStyle.Render(m.confirm.prompt),
+		)
+	case uiModeDashboard:
+		return m.dashboardView()
+	default:
+		tableView := m.list.table.View()
+		displayed := 0
+		if tableView != "" {
+			lines := strings.Split(tableView, "\n")
+			if len(lines) > 1 {
+				for _, line := range lines[1:] {
+					if strings.TrimSpace(stripANSI(line)) != "" {
+						displayed++
+					}
+				}
+			}
+		}
+		infoLine := fmt.Sprintf("Total: %d  Matched: %d  Visible: %d", len(m.allHosts), len(m.filteredIx), displayed)
+		footer := statusStyle.Render("Enter connect  / search  Ctrl+A/E/D add/edit/delete  Ctrl+R import  Ctrl+S ssh_config  Ctrl+B dashboard  Ctrl+C or q quit")
+		if m.status != "" {
+			footer += "\n" + statusStyle.Render(m.status)
+		}
+		return baseStyle.Render(
+			headerStyle.Render("shh - SSH helper") + "\n" +
+				m.list.search.View() + "\n\n" +
+				tableView + "\n" +
+				infoLine + "\n" +
+				footer,
+		)
+	}
+}
+
+func (m *Model) currentSelection() (Host, bool) {
+	if len(m.filteredIx) == 0 {
+		return Host{}, false
+	}
+	row := m.list.table.Cursor()
+	if row < 0 || row >= len(m.filteredIx) {
+		return Host{}, false
+	}
+	return m.allHosts[m.filteredIx[row]], true
+}
+
+func stripANSI(s string) string {
+	return ansiRegexp.ReplaceAllString(s, "")
+}
+
+func defaultColumns() []table.Column {
+	return []table.Column{
+		{Title: "Host", Width: 36},
+		{Title: "Comment", Width: 52},
+		{Title: "Src", Width: 6},
+		{Title: "Last Used", Width: 19},
+		{Title: "#", Width: 4},
+	}
+}
+
+func responsiveColumns(width int) []table.Column {
+	const (
+		lastUsedWidth = 19
+		countWidth    = 4
+		srcWidth      = 6
+		minHostWidth  = 16
+		minComment    = 16
+		padding       = 8
+	)
+	available := max(minHostWidth+minComment, width-padding-lastUsedWidth-countWidth-srcWidth)
+	hostWidth := available / 2
+	commentWidth := available - hostWidth
+
+	if hostWidth < minHostWidth {
+		hostWidth = minHostWidth
+		commentWidth = available - hostWidth
+	}
+	if commentWidth < minComment {
+		commentWidth = minComment
+		hostWidth = available - commentWidth
+		if hostWidth < minHostWidth {
+			hostWidth = minHostWidth
+		}
+	}
+
+	return []table.Column{
+		{Title: "Host", Width: hostWidth},
+		{Title: "Comment", Width: commentWidth},
+		{Title: "Src", Width: srcWidth},
+		{Title: "Last Used", Width: lastUsedWidth},
+		{Title: "#", Width: countWidth},
+	}
+}
+
+// displayComment renders the comment column: structured ssh_config metadata
+// is summarized instead of dumping raw JSON.
+func displayComment(h Host) string {
+	if h.Source != SourceSSHConfig {
+		return h.Comment
+	}
+	var entry SSHConfigEntry
+	if err := json.Unmarshal([]byte(h.Comment), &entry); err != nil {
+		return h.Comment
+	}
+	target := entry.HostName
+	if entry.User != "" {
+		target = entry.User + "@" + target
+	}
+	if entry.Port != "" {
+		target += ":" + entry.Port
+	}
+	if entry.ProxyJump != "" {
+		target += " via " + entry.ProxyJump
+	}
+	if entry.Comment != "" {
+		target += " # " + entry.Comment
+	}
+	return target
+}
+
+func sourceLabel(source string) string {
+	switch source {
+	case SourceSSHConfig:
+		return "config"
+	case SourceKnownHosts:
+		return "known"
+	case SourceHistory:
+		return "hist"
+	default:
+		return "manual"
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,53 @@
+package shh
+
+import "testing"
+
+func TestSplitTagQuery(t *testing.T) {
+	t.Parallel()
+	tags, rest := splitTagQuery("tag:prod tag:EU-West web")
+	if len(tags) != 2 || tags[0] != "prod" || tags[1] != "eu-west" {
+		t.Fatalf("tags = %v, want [prod eu-west]", tags)
+	}
+	if rest != "web" {
+		t.Fatalf("rest = %q, want %q", rest, "web")
+	}
+}
+
+func TestSplitTagQueryNoTags(t *testing.T) {
+	t.Parallel()
+	tags, rest := splitTagQuery("example.com")
+	if tags != nil {
+		t.Fatalf("tags = %v, want nil", tags)
+	}
+	if rest != "example.com" {
+		t.Fatalf("rest = %q, want %q", rest, "example.com")
+	}
+}
+
+func TestHasAllTags(t *testing.T) {
+	t.Parallel()
+	host := []string{"prod", "db"}
+	if !hasAllTags(host, nil) {
+		t.Fatalf("empty want should always match")
+	}
+	if !hasAllTags(host, []string{"prod"}) {
+		t.Fatalf("expected match on subset")
+	}
+	if hasAllTags(host, []string{"prod", "eu-west"}) {
+		t.Fatalf("expected no match when a wanted tag is missing")
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	t.Parallel()
+	got := parseTags(" Prod, db ,, prod")
+	want := []string{"prod", "db"}
+	if len(got) != len(want) {
+		t.Fatalf("parseTags = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("parseTags = %v, want %v", got, want)
+		}
+	}
+}
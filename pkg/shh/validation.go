@@ -0,0 +1,112 @@
+package shh
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	safeHost = regexp.MustCompile(`^(?:[A-Za-z0-9._-]+|\[[0-9A-Fa-f:]+\])$`)
+	safeUser = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// NormalizeHost trims raw and validates it as a host/alias suitable for
+// storage and for passing to ssh.
+func NormalizeHost(raw string) (string, error) {
+	host := strings.TrimSpace(raw)
+	if host == "" {
+		return "", fmt.Errorf("host cannot be empty")
+	}
+	if strings.Contains(host, " ") {
+		return "", fmt.Errorf("host cannot contain spaces")
+	}
+	if !safeHost.MatchString(host) {
+		return "", fmt.Errorf("invalid host format")
+	}
+	return host, nil
+}
+
+// IsSafeHost reports whether host is safe to interpolate into an ssh
+// argument list, i.e. it would pass NormalizeHost's format check.
+func IsSafeHost(host string) bool {
+	return safeHost.MatchString(host)
+}
+
+// HostSpec bundles a host alias with the connection metadata AddHost and
+// UpdateHost need: who to log in as, which port to use, and which bastions
+// to jump through on the way there. Every field but Host is optional.
+type HostSpec struct {
+	Host      string
+	Comment   string
+	User      string
+	Port      string
+	ProxyJump string
+}
+
+// NormalizeHostSpec validates and trims every field of spec. User must look
+// like a POSIX username, Port (if set) must be a number in 1-65535, and each
+// comma-separated ProxyJump alias must already resolve against store, since a
+// bastion ssh can't reach isn't useful to jump through.
+func NormalizeHostSpec(ctx context.Context, store Store, spec HostSpec) (HostSpec, error) {
+	host, err := NormalizeHost(spec.Host)
+	if err != nil {
+		return HostSpec{}, err
+	}
+	spec.Host = host
+	spec.Comment = strings.TrimSpace(spec.Comment)
+
+	spec.User = strings.TrimSpace(spec.User)
+	if spec.User != "" && !safeUser.MatchString(spec.User) {
+		return HostSpec{}, fmt.Errorf("invalid user %q", spec.User)
+	}
+
+	spec.Port = strings.TrimSpace(spec.Port)
+	if spec.Port != "" {
+		port, err := strconv.Atoi(spec.Port)
+		if err != nil || port < 1 || port > 65535 {
+			return HostSpec{}, fmt.Errorf("invalid port %q", spec.Port)
+		}
+	}
+
+	proxyJump, err := normalizeProxyJump(ctx, store, spec.ProxyJump)
+	if err != nil {
+		return HostSpec{}, err
+	}
+	spec.ProxyJump = proxyJump
+
+	return spec, nil
+}
+
+// normalizeProxyJump trims raw and checks that every comma-separated alias in
+// it resolves against a known host in store.
+func normalizeProxyJump(ctx context.Context, store Store, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	hosts, err := store.ListHosts(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolve proxy jump: %w", err)
+	}
+	known := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		known[h.Host] = struct{}{}
+	}
+
+	aliases := strings.Split(raw, ",")
+	out := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+		if _, ok := known[alias]; !ok {
+			return "", fmt.Errorf("proxy jump host %q is not a known host", alias)
+		}
+		out = append(out, alias)
+	}
+	return strings.Join(out, ","), nil
+}
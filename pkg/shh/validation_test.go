@@ -0,0 +1,124 @@
+package shh
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "trimmed host",
+			input: " example.com ",
+			want:  "example.com",
+		},
+		{
+			name:  "alias",
+			input: "my-alias",
+			want:  "my-alias",
+		},
+		{
+			name:  "ipv6",
+			input: "[2001:db8::1]",
+			want:  "[2001:db8::1]",
+		},
+		{
+			name:    "empty",
+			input:   "   ",
+			wantErr: true,
+		},
+		{
+			name:    "spaces inside",
+			input:   "bad host",
+			wantErr: true,
+		},
+		{
+			name:    "shell injection",
+			input:   "localhost;rm -rf /",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NormalizeHost(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeHost(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeHost(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Fatalf("NormalizeHost(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeHostSpec(t *testing.T) {
+	t.Parallel()
+	store := &fakeStore{hosts: []Host{{ID: 1, Host: "bastion1"}, {ID: 2, Host: "bastion2"}}}
+
+	tests := []struct {
+		name    string
+		spec    HostSpec
+		wantErr bool
+	}{
+		{
+			name: "minimal",
+			spec: HostSpec{Host: "example.com"},
+		},
+		{
+			name: "full",
+			spec: HostSpec{Host: "example.com", User: "deploy", Port: "2222", ProxyJump: "bastion1, bastion2"},
+		},
+		{
+			name:    "bad user",
+			spec:    HostSpec{Host: "example.com", User: "deploy user"},
+			wantErr: true,
+		},
+		{
+			name:    "port out of range",
+			spec:    HostSpec{Host: "example.com", Port: "70000"},
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric port",
+			spec:    HostSpec{Host: "example.com", Port: "ssh"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown proxy jump",
+			spec:    HostSpec{Host: "example.com", ProxyJump: "nope"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NormalizeHostSpec(context.Background(), store, tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeHostSpec(%+v) expected error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeHostSpec(%+v) unexpected error: %v", tt.spec, err)
+			}
+		})
+	}
+}